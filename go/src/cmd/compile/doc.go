@@ -61,6 +61,13 @@ Flags:
 	-goversion string
 		Specify required go tool version of the runtime.
 		Exits when the runtime go version does not match goversion.
+	-gosbdefer
+		Run the epilog of sandboxes as a deferred call, instead of on
+		every return path of the sandbox. Useful to measure the cost of
+		sandbox transitions.
+	-gosbreport file
+		Write a JSON description of the sandboxes declared in the package
+		to file: their id, configuration, dependencies and position.
 	-h
 		Halt with a stack trace at the first error detected.
 	-importcfg file
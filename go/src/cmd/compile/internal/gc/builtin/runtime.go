@@ -19,6 +19,7 @@ func sandbox_prolog(id string, mem string, sys string)
 func sandbox_epilog(id string, mem string, sys string)
 
 func newobject(typ *byte, id int) *any
+func newsandboxobject(typ *byte, id int, sb string) *any
 func panicdivide()
 func panicshift()
 func panicmakeslicelen()
@@ -110,6 +110,13 @@ func typecheckclosure(clo *Node, top int) {
 	}
 
 	xfunc.Func.Nname.Sym = closurename(Curfn)
+	// @aghosn remember where sandboxes are declared.
+	if xfunc.IsSandbox {
+		if sandboxOuter == nil {
+			sandboxOuter = make(map[*Node]*Node)
+		}
+		sandboxOuter[xfunc] = Curfn
+	}
 	disableExport(xfunc.Func.Nname.Sym)
 	declare(xfunc.Func.Nname, PFUNC)
 	xfunc = typecheck(xfunc, ctxStmt)
@@ -200,6 +207,9 @@ func capturevars(xfunc *Node) {
 		} else {
 			outermost.Name.SetAddrtaken(true)
 			outer = nod(OADDR, outer, nil)
+			if xfunc.IsSandbox {
+				sandboxCapture(outermost, xfunc)
+			}
 		}
 
 		if Debug['m'] > 1 {
@@ -417,6 +427,11 @@ func walkclosure(clo *Node, init *Nodes) *Node {
 		delete(prealloc, clo)
 	}
 
+	// @aghosn the sandbox reads its captured variables from the closure.
+	if xfunc.IsSandbox {
+		sandboxAlloc = xfunc
+		defer func() { sandboxAlloc = nil }()
+	}
 	return walkexpr(clos, init)
 }
 
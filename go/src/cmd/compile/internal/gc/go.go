@@ -302,6 +302,7 @@ var (
 	msanread,
 	msanwrite,
 	newobject,
+	newsandboxobject,
 	newproc,
 	panicdivide,
 	panicshift,
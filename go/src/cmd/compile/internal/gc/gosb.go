@@ -5,7 +5,15 @@ import (
 	"cmd/compile/internal/syntax"
 	"cmd/compile/internal/types"
 	"cmd/internal/bio"
+	"cmd/internal/obj"
+	"cmd/internal/objabi"
+	"cmd/internal/src"
+	"encoding/json"
 	"fmt"
+	"gosb/commons"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 type Pkg = types.Pkg
@@ -16,10 +24,50 @@ var sandboxes []*Node
 
 var sandboxToPkgs map[*Node][]*Pkg
 
-const (
-	SandboxHeader = "\xeegosandx"
-	SandboxFooter = "\xefgosandx"
-)
+// sandboxOuter maps sandbox closures to their enclosing function, nil for
+// closures declared at the package level.
+var sandboxOuter map[*Node]*Node
+
+// gosbreport is the file to which the sandbox report is written, if any.
+var gosbreport string
+
+// unsafeUse is a construct that lets a package escape the memory view of
+// the sandboxes it runs in, e.g., an import of unsafe.
+type unsafeUse struct {
+	kind string // "unsafe", "linkname", "asm" or "cgo"
+	pos  string // "-" when unknown
+	sym  string
+}
+
+// unsafeUses records the unsafe constructs of the package being compiled.
+// They are dumped in the archive so that the linker can reject them in
+// packages pulled into a strict sandbox. The standard library is trusted
+// and never recorded.
+var unsafeUses []unsafeUse
+
+// recordUnsafe records an unsafe construct at pos.
+func recordUnsafe(kind string, pos src.XPos, sym string) {
+	if compiling_std {
+		return
+	}
+	unsafeUses = append(unsafeUses, unsafeUse{kind, linestr(pos), sym})
+}
+
+// recordAsmUnsafe records the functions that the package implements in
+// assembly, as listed in the symabis file.
+func recordAsmUnsafe() {
+	if compiling_std {
+		return
+	}
+	var syms []string
+	for s := range symabiDefs {
+		syms = append(syms, myimportpath+"."+strings.TrimPrefix(s, `"".`))
+	}
+	sort.Strings(syms)
+	for _, s := range syms {
+		unsafeUses = append(unsafeUses, unsafeUse{"asm", "-", s})
+	}
+}
 
 func (n *Node) SandboxName() string {
 	if !n.IsSandbox || n.Op != ODCLFUNC || n.Func == nil || n.Func.Nname == nil {
@@ -32,8 +80,14 @@ func registerSandboxes(top []*Node) {
 	if sandboxToPkgs == nil {
 		sandboxToPkgs = make(map[*Node][]*Pkg)
 	}
+	ids := make(map[string]*Node)
 	for _, v := range top {
 		if v.Op == ODCLFUNC && v.IsSandbox {
+			if prev, ok := ids[v.Id]; ok {
+				yyerrorl(v.Pos, "duplicate sandbox name %s, previous sandbox at %v", v.Id, linestr(prev.Pos))
+				continue
+			}
+			ids[v.Id] = v
 			sandboxes = append(sandboxes, v)
 			pkgs := gatherPackages(v)
 			if _, ok := sandboxToPkgs[v]; ok {
@@ -129,48 +183,89 @@ func getPackage(n *Node) *Pkg {
 	return nil
 }
 
-// dumpSandObj dumps sandbox information inside the archive.
-func dumpSandObj(bout *bio.Writer) {
-	printSandObjHeader(bout)
-	dumpSandboxes(bout)
-	printSandObjFooter(bout)
+// sandboxCaptures maps the variables captured by reference by a sandbox
+// closure to that sandbox.
+var sandboxCaptures map[*Node]*Node
+
+// sandboxAlloc is the sandbox whose captured state is being allocated by
+// callnew, if any.
+var sandboxAlloc *Node
+
+// sandboxCapture records that the sandbox xfunc captures v by reference.
+// Such variables, as well as the closure record of the sandbox, are
+// allocated in memory attributed to the sandbox, so that the sandbox does
+// not need the enclosing package in its view to access them. A variable
+// captured by several sandboxes belongs to the first one, the other ones
+// still need the enclosing package in their view.
+func sandboxCapture(v, xfunc *Node) {
+	if sandboxCaptures == nil {
+		sandboxCaptures = make(map[*Node]*Node)
+	}
+	if _, ok := sandboxCaptures[v]; !ok {
+		sandboxCaptures[v] = xfunc
+	}
 }
 
-// printSandObjHeader writes the go sandboxes header, that for the moment
-// contains only the number of entries.
-func printSandObjHeader(bout *bio.Writer) {
-	fmt.Fprintf(bout, "%v\n", SandboxHeader)
-	fmt.Fprintf(bout, "%v\n", len(sandboxes))
-	bout.Flush()
+// sandboxOwner returns the name under which the runtime knows the memory
+// attributed to the sandbox xfunc, i.e., the name of its function.
+func sandboxOwner(xfunc *Node) *Node {
+	n := nodstr(myimportpath + "." + xfunc.SandboxName())
+	return defaultlit(typecheck(n, ctxExpr), types.Types[TSTRING])
 }
 
-// printSandObjFooter footer for a sandbox object entry
-func printSandObjFooter(bout *bio.Writer) {
-	fmt.Fprintf(bout, "%v\n", SandboxFooter)
-	bout.Flush()
+// sandboxEpilog appends the epilog of the sandbox fn to its exit code, so
+// that it runs on every return path, after the deferred calls of fn. When a
+// panic unwinds fn, the runtime runs the epilog instead, using the frame
+// that the prolog recorded. This requires fn not to be inlined.
+func sandboxEpilog(fn *Node) {
+	lno := lineno
+	lineno = fn.Func.Endlineno
+	var init Nodes
+	call := mkcall("sandbox_epilog", nil, &init,
+		nodstr(unquoteSandboxConfig(fn.Id)),
+		nodstr(unquoteSandboxConfig(fn.Mem)),
+		nodstr(unquoteSandboxConfig(fn.Sys)))
+	fn.Func.Exit.Append(init.Slice()...)
+	fn.Func.Exit.Append(call)
+	lineno = lno
 }
 
-// dumpSandboxes dumps all the sandboxes.
-func dumpSandboxes(bout *bio.Writer) {
+// dumpGosbSym records the sandboxes and the unsafe constructs of the package
+// in a symbol of kind SGOSB, that the linker reads along with the object file.
+func dumpGosbSym() {
+	if len(sandboxes) == 0 && len(unsafeUses) == 0 {
+		return
+	}
+	sbs := make([]commons.ObjSandbox, 0, len(sandboxes))
 	for _, s := range sandboxes {
-		dumpSandbox(s, bout)
+		// Sanity checks
+		if s == nil || !s.IsSandbox || len(s.Id) == 0 || len(s.Mem) == 0 || len(s.Sys) == 0 {
+			panic("Malformed sandbox")
+		}
+		if _, ok := sandboxToPkgs[s]; !ok {
+			panic("Missing package information for sandbox")
+		}
+		sbs = append(sbs, commons.ObjSandbox{
+			Func:     myimportpath + "." + s.SandboxName(),
+			Id:       s.Id,
+			Mem:      s.Mem,
+			Sys:      s.Sys,
+			Packages: sandboxPackages(s),
+		})
 	}
+	uses := make([]commons.ObjUnsafe, 0, len(unsafeUses))
+	for _, u := range unsafeUses {
+		uses = append(uses, commons.ObjUnsafe{Kind: u.kind, Pos: u.pos, Sym: u.sym})
+	}
+	data := commons.EncodeObject(sbs, uses)
+	lsym := Ctxt.Lookup("go.gosb.obj." + objabi.PathToPrefix(myimportpath))
+	lsym.Type = objabi.SGOSB
+	lsym.P = data
+	ggloblsym(lsym, int32(len(data)), obj.LOCAL)
 }
 
-// dumpSandbox writes a sandbox information to the object file.
-func dumpSandbox(s *Node, bout *bio.Writer) {
-	// Sanity checks
-	if s == nil || !s.IsSandbox || len(s.Id) == 0 || len(s.Mem) == 0 || len(s.Sys) == 0 {
-		panic("Malformed sandbox")
-	}
-	if _, ok := sandboxToPkgs[s]; !ok {
-		panic("Missing package information for sandbox")
-	}
-	// dump the sandbox symbol.
-	fmt.Fprintf(bout, "%v\n", myimportpath+"."+s.SandboxName())
-	// dump the sandbox configuration
-	fmt.Fprintf(bout, "%v;%v;%v\n", s.Id, s.Mem, s.Sys)
-	// dump package dependencies
+// sandboxPackages returns the paths of the packages the sandbox s depends on.
+func sandboxPackages(s *Node) []string {
 	unfilteredpkgs, _ := sandboxToPkgs[s]
 
 	// filter unwanted packages
@@ -181,11 +276,60 @@ func dumpSandbox(s *Node, bout *bio.Writer) {
 		}
 		pkgs = append(pkgs, p.Path)
 	}
+	return pkgs
+}
+
+// SandboxReport describes a sandbox in the report written by -gosbreport.
+type SandboxReport struct {
+	Id        string
+	Func      string
+	Enclosing string
+	Mem       string
+	Sys       string
+	Packages  []string
+	Pos       string
+}
+
+// dumpSandboxReport writes a description of the sandboxes of the package
+// to the file gosbreport, in JSON.
+func dumpSandboxReport() {
+	reports := make([]SandboxReport, 0, len(sandboxes))
+	for _, s := range sandboxes {
+		r := SandboxReport{
+			Id:       unquoteSandboxConfig(s.Id),
+			Func:     myimportpath + "." + s.SandboxName(),
+			Mem:      unquoteSandboxConfig(s.Mem),
+			Sys:      unquoteSandboxConfig(s.Sys),
+			Packages: sandboxPackages(s),
+			Pos:      linestr(s.Pos),
+		}
+		if outer, ok := sandboxOuter[s]; !ok {
+			// //go:sandbox functions enclose themselves.
+			r.Enclosing = r.Func
+		} else if outer != nil {
+			r.Enclosing = myimportpath + "." + outer.funcname()
+		}
+		reports = append(reports, r)
+	}
+	b, err := bio.Create(gosbreport)
+	if err != nil {
+		Fatalf("%v", err)
+	}
+	enc := json.NewEncoder(b)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(reports); err != nil {
+		Fatalf("writing sandbox report: %v", err)
+	}
+	b.Close()
+}
 
-	fmt.Fprintf(bout, "%v\n", len(pkgs))
-	for _, p := range pkgs {
-		fmt.Fprintf(bout, "%v\n", p)
+// unquoteSandboxConfig returns the value of a sandbox configuration string.
+func unquoteSandboxConfig(s string) string {
+	v, err := strconv.Unquote(s)
+	if err != nil {
+		Fatalf("malformed sandbox configuration %s", s)
 	}
+	return v
 }
 
 // ssa external function
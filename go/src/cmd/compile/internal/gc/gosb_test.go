@@ -0,0 +1,145 @@
+package gc
+
+import (
+	"encoding/json"
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const sandboxSrc = `package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func f() func() {
+	n := 0
+	g := sandbox "upper" ["strings:R", "io"] () {
+		n = len(strings.ToUpper("a"))
+	}
+	fmt.Println(n)
+	return g
+}
+
+//go:sandbox "fmt:R" ""
+func h() {
+	fmt.Println(strings.Repeat("b", 2))
+}
+`
+
+// compileSandboxes compiles src as package main with the extra flags and
+// returns the output of the compiler, failing the test on error.
+func compileSandboxes(t *testing.T, dir, src string, flags ...string) string {
+	file := filepath.Join(dir, "x.go")
+	if err := ioutil.WriteFile(file, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	args := append([]string{"tool", "compile", "-p", "main", "-o", filepath.Join(dir, "x.o")}, flags...)
+	out, err := exec.Command(testenv.GoToolPath(t), append(args, file)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("compile: %v\n%s", err, out)
+	}
+	return string(out)
+}
+
+func TestSandboxReport(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestSandboxReport")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	report := filepath.Join(dir, "report.json")
+	compileSandboxes(t, dir, sandboxSrc, "-gosbreport="+report)
+	data, err := ioutil.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []SandboxReport
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("malformed report: %v\n%s", err, data)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d sandboxes, want 2:\n%s", len(got), data)
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i].Func < got[j].Func })
+	want := []SandboxReport{
+		{Id: "upper", Func: "main.f.func1", Enclosing: "main.f", Mem: "strings:R", Sys: "io", Packages: []string{"strings"}, Pos: "x.go:10:7"},
+		{Func: "main.h", Enclosing: "main.h", Mem: "fmt:R", Packages: []string{"fmt", "strings"}, Pos: "x.go:18:6"},
+	}
+	for i := range want {
+		g := got[i]
+		if !strings.HasSuffix(g.Pos, want[i].Pos) {
+			t.Errorf("sandbox %s at %s, want %s", g.Func, g.Pos, want[i].Pos)
+		}
+		g.Pos = want[i].Pos
+		// The packages also include those the calls pull in, e.g., os for
+		// fmt.Println.
+		pkgs := make(map[string]bool)
+		for _, p := range g.Packages {
+			pkgs[p] = true
+		}
+		for _, p := range want[i].Packages {
+			if !pkgs[p] {
+				t.Errorf("sandbox %s: %s missing from packages %v", g.Func, p, g.Packages)
+			}
+		}
+		g.Packages = want[i].Packages
+		// The id of //go:sandbox functions is generated.
+		if want[i].Id == "" {
+			g.Id = ""
+		}
+		if !reflect.DeepEqual(g, want[i]) {
+			t.Errorf("got %+v, want %+v", g, want[i])
+		}
+	}
+}
+
+func TestSandboxCapturedAlloc(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestSandboxCapturedAlloc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// n escapes with the sandbox, both are allocated on behalf of it.
+	out := compileSandboxes(t, dir, sandboxSrc, "-S")
+	var f []string
+	for _, l := range strings.Split(out, "\n") {
+		if strings.HasPrefix(l, `"".`) {
+			f = append(f, "")
+		}
+		if len(f) > 0 {
+			f[len(f)-1] += l + "\n"
+		}
+	}
+	var body string
+	for _, s := range f {
+		if strings.HasPrefix(s, `"".f STEXT`) {
+			body = s
+		}
+	}
+	if body == "" {
+		t.Fatalf("no assembly for f:\n%s", out)
+	}
+	if !strings.Contains(body, "runtime.newsandboxobject(SB)") {
+		t.Errorf("captured state of the sandbox not allocated with runtime.newsandboxobject:\n%s", body)
+	}
+	if !strings.Contains(body, `go.string."main.f.func1"`) {
+		t.Errorf("captured state of the sandbox not attributed to main.f.func1:\n%s", body)
+	}
+}
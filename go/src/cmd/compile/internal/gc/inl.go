@@ -129,6 +129,12 @@ func caninl(fn *Node) {
 		return
 	}
 
+	// Sandboxes need their own frame, see sandboxEpilog.
+	if fn.IsSandbox {
+		reason = "sandbox"
+		return
+	}
+
 	// If marked "go:norace" and -race compilation, don't inline.
 	if flag_race && fn.Func.Pragma&Norace != 0 {
 		reason = "marked go:norace with -race compilation"
@@ -266,6 +266,8 @@ func Main(archInit func(*Arch)) {
 	flag.Int64Var(&memprofilerate, "memprofilerate", 0, "set runtime.MemProfileRate to `rate`")
 	var goversion string
 	flag.StringVar(&goversion, "goversion", "", "required version of the runtime")
+	flag.StringVar(&gosbreport, "gosbreport", "", "write a JSON report of the package's sandboxes to `file`")
+	flag.BoolVar(&syntax.SandboxDefer, "gosbdefer", false, "run sandbox epilogs as deferred calls")
 	var symabisPath string
 	flag.StringVar(&symabisPath, "symabis", "", "read symbol ABIs from `file`")
 	flag.StringVar(&traceprofile, "traceprofile", "", "write an execution trace to `file`")
@@ -286,6 +288,10 @@ func Main(archInit func(*Arch)) {
 		maxImplicitStackVarSize = 16 * 1024
 	}
 
+	// @aghosn the parser validates sandbox configurations against the package.
+	syntax.ImportPath = myimportpath
+	syntax.CompilingStd = compiling_std
+
 	Ctxt.Flag_shared = flag_dynlink || flag_shared
 	Ctxt.Flag_dynlink = flag_dynlink
 	Ctxt.Flag_optimize = Debug['N'] == 0
@@ -315,6 +321,7 @@ func Main(archInit func(*Arch)) {
 
 	if symabisPath != "" {
 		readSymABIs(symabisPath, myimportpath)
+		recordAsmUnsafe()
 	}
 
 	thearch.LinkArch.Init(Ctxt)
@@ -750,6 +757,9 @@ func Main(archInit func(*Arch)) {
 	if asmhdr != "" {
 		dumpasmhdr()
 	}
+	if gosbreport != "" {
+		dumpSandboxReport()
+	}
 
 	// Check whether any of the functions we have compiled have gigantic stack frames.
 	sort.Slice(largeStackFrames, func(i, j int) bool {
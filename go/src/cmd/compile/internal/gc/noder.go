@@ -251,6 +251,9 @@ func (p *noder) node() {
 		s := lookup(n.local)
 		if n.remote != "" {
 			s.Linkname = n.remote
+			if !isCgoGeneratedFile(n.pos) {
+				recordUnsafe("linkname", p.makeXPos(n.pos), n.local+" -> "+n.remote)
+			}
 		} else {
 			// Use the default object symbol name if the
 			// user didn't provide one.
@@ -324,6 +327,14 @@ func (p *noder) importDecl(imp *syntax.ImportDecl) {
 	}
 
 	ipkg.Direct = true
+	if ipkg == unsafepkg {
+		// cgo-generated files always import unsafe, record cgo itself.
+		if isCgoGeneratedFile(imp.Pos()) {
+			recordUnsafe("cgo", p.pos(imp), "C")
+		} else {
+			recordUnsafe("unsafe", p.pos(imp), "unsafe")
+		}
+	}
 
 	var my *types.Sym
 	if imp.LocalPkgName != nil {
@@ -493,6 +504,9 @@ func (p *noder) funcDecl(fun *syntax.FuncDecl) *Node {
 	f.Func.Nname.Name.Defn = f
 	f.Func.Nname.Name.Param.Ntype = t
 
+	// @aghosn keep track of //go:sandbox functions
+	f.IsSandbox, f.Id, f.Mem, f.Sys = fun.IsSandbox, fun.Id, fun.Mem, fun.Sys
+
 	pragma := fun.Pragma
 	f.Func.Pragma = fun.Pragma
 	if pragma&Systemstack != 0 && pragma&Nosplit != 0 {
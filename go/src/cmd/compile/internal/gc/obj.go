@@ -72,12 +72,6 @@ func dumpobj1(outfile string, mode int) {
 		dumpLinkerObj(bout)
 		finishArchiveEntry(bout, start, "_go_.o")
 	}
-	// Dump the sandboxes
-	if len(sandboxes) > 0 {
-		start := startArchiveEntry(bout)
-		dumpSandObj(bout)
-		finishArchiveEntry(bout, start, "__.SANDBOX")
-	}
 }
 
 func printObjHeader(bout *bio.Writer) {
@@ -169,6 +163,8 @@ func dumpLinkerObj(bout *bio.Writer) {
 	}
 
 	addGCLocals()
+	// @aghosn, sandboxes and unsafe constructs for the linker.
+	dumpGosbSym()
 
 	obj.WriteObjFile(Ctxt, bout.Writer, myimportpath)
 }
@@ -82,6 +82,7 @@ func initssaconfig() {
 	msanread = sysfunc("msanread")
 	msanwrite = sysfunc("msanwrite")
 	newobject = sysfunc("newobject")
+	newsandboxobject = sysfunc("newsandboxobject")
 	newproc = sysfunc("newproc")
 	panicdivide = sysfunc("panicdivide")
 	panicdottypeE = sysfunc("panicdottypeE")
@@ -2488,6 +2489,10 @@ func (s *state) expr(n *Node) *ssa.Value {
 		typ := s.expr(n.Left)
 		//TODO(aghosn) add argument here?
 		pkgId := newobjectPkgArg(s)
+		if n.Right != nil {
+			sb := s.expr(n.Right)
+			return s.rtcall(newsandboxobject, true, []*types.Type{n.Type}, typ, pkgId, sb)[0]
+		}
 		vv := s.rtcall(newobject, true, []*types.Type{n.Type}, typ, pkgId)
 		return vv[0]
 
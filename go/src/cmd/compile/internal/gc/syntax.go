@@ -675,7 +675,7 @@ const (
 	OAND         // Left & Right
 	OANDNOT      // Left &^ Right
 	ONEW         // new(Left); corresponds to calls to new in source code
-	ONEWOBJ      // runtime.newobject(n.Type); introduced by walk; Left is type descriptor
+	ONEWOBJ      // runtime.newobject(n.Type); introduced by walk; Left is type descriptor, Right the sandbox owning the object, if any
 	ONOT         // !Left
 	OBITNOT      // ^Left
 	OPLUS        // +Left
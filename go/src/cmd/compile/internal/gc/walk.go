@@ -5,6 +5,7 @@
 package gc
 
 import (
+	"cmd/compile/internal/syntax"
 	"cmd/compile/internal/types"
 	"cmd/internal/objabi"
 	"cmd/internal/sys"
@@ -69,6 +70,9 @@ func walk(fn *Node) {
 
 	zeroResults()
 	heapmoves()
+	if fn.IsSandbox && !syntax.SandboxDefer {
+		sandboxEpilog(fn)
+	}
 	if Debug['W'] != 0 && Curfn.Func.Enter.Len() > 0 {
 		s := fmt.Sprintf("enter %v", Curfn.Func.Nname.Sym)
 		dumplist(s, Curfn.Func.Enter)
@@ -198,7 +202,9 @@ func walkstmt(n *Node) *Node {
 				yyerror("%v escapes to heap, not allowed in runtime", v)
 			}
 			if prealloc[v] == nil {
+				sandboxAlloc = sandboxCaptures[v]
 				prealloc[v] = callnew(v.Type)
+				sandboxAlloc = nil
 			}
 			nn := nod(OAS, v.Name.Param.Heapaddr, prealloc[v])
 			nn.SetColas(true)
@@ -1919,6 +1925,9 @@ func callnew(t *types.Type) *Node {
 	dowidth(t)
 	//TODO aghosn add another argument here?
 	n := nod(ONEWOBJ, typename(t), nil)
+	if sandboxAlloc != nil {
+		n.Right = sandboxOwner(sandboxAlloc)
+	}
 	n.Type = types.NewPtr(t)
 	n.SetTypecheck(1)
 	n.SetNonNil(true)
@@ -1,7 +1,9 @@
 package syntax
 
 import (
+	"gosb/commons"
 	"strconv"
+	"strings"
 )
 
 var sandboxCounter int = 0
@@ -9,6 +11,18 @@ var sandboxCounter int = 0
 // Identifier for the package
 var PkgId int
 
+// Import path of the package being compiled and whether it belongs to the
+// standard library. They are used to validate sandbox memory views.
+var (
+	ImportPath   string
+	CompilingStd bool
+)
+
+// SandboxDefer selects the original lowering of sandbox epilogs, as a
+// deferred call. Otherwise, the compiler runs the epilog on every return
+// path and the runtime runs it when a panic unwinds the sandbox.
+var SandboxDefer bool
+
 //TODO(aghosn) see if we want to have two ints instead of a string
 func generateSandboxId() *BasicLit {
 	b := new(BasicLit)
@@ -18,6 +32,42 @@ func generateSandboxId() *BasicLit {
 	return b
 }
 
+// namedSandboxId turns the optional name of a sandbox literal into its id.
+// Named ids are stable across builds, as opposed to generated ones, and can
+// therefore be referred to from outside the binary.
+func (p *parser) namedSandboxId(name *BasicLit) *BasicLit {
+	if name.Kind != StringLit || name.Bad {
+		p.errorAt(name.pos, "sandbox name must be a string literal")
+		return generateSandboxId()
+	}
+	value, err := strconv.Unquote(name.Value)
+	if err != nil || !IsValidSandboxName(value) {
+		p.errorAt(name.pos, "invalid sandbox name "+name.Value)
+		return generateSandboxId()
+	}
+	b := new(BasicLit)
+	b.Kind = StringLit
+	b.Value = strconv.Quote(value)
+	return b
+}
+
+// IsValidSandboxName reports whether name can be used as a sandbox id.
+// Names start with a letter and only contain letters, digits, '_', '-', '.'
+// and '/'. This ensures they never collide with generated ids, which contain
+// a ':', or with the trusted domain id "-1".
+func IsValidSandboxName(name string) bool {
+	if len(name) == 0 || !isLetter(rune(name[0])) {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := rune(name[i])
+		if !isLetter(c) && !isDecimal(c) && c != '-' && c != '.' && c != '/' {
+			return false
+		}
+	}
+	return true
+}
+
 func (p *parser) sandboxConfig() (string, string, string, []Stmt) {
 	if trace {
 		defer p.trace("sandboxType")()
@@ -25,14 +75,32 @@ func (p *parser) sandboxConfig() (string, string, string, []Stmt) {
 
 	pos := p.pos()
 
+	// Parse the optional name, i.e., sandbox "name" [...]
+	name := p.oliteral()
+
 	// Parse ["mem", "sys"], generate unique sandbox id
 	p.want(_Lbrack)
-	memory := p.oliteral()
+	memory := p.sandboxLiteral()
 	p.want(_Comma)
-	syscalls := p.oliteral()
+	syscalls := p.sandboxLiteral()
 	p.want(_Rbrack)
 
-	id := generateSandboxId()
+	return p.sandboxStmts(pos, name, memory, syscalls)
+}
+
+// sandboxStmts validates the configuration of a sandbox and generates its id
+// along with the prolog and, with SandboxDefer, the deferred epilog
+// statements to prepend to its body.
+func (p *parser) sandboxStmts(pos Pos, name, memory, syscalls *BasicLit) (string, string, string, []Stmt) {
+	p.checkMemoryView(memory)
+	p.checkSyscalls(syscalls)
+
+	var id *BasicLit
+	if name != nil {
+		id = p.namedSandboxId(name)
+	} else {
+		id = generateSandboxId()
+	}
 	id.pos = memory.pos
 
 	config := []Expr{id, memory, syscalls}
@@ -42,6 +110,9 @@ func (p *parser) sandboxConfig() (string, string, string, []Stmt) {
 	prologStmt := new(ExprStmt)
 	prologStmt.X = prolog
 	prologStmt.pos = pos
+	if !SandboxDefer {
+		return id.Value, memory.Value, syscalls.Value, []Stmt{prologStmt}
+	}
 
 	epilog_call := sandboxGenerateCall("sandbox_epilog", config)
 	epilogStmt := new(CallStmt)
@@ -51,6 +122,178 @@ func (p *parser) sandboxConfig() (string, string, string, []Stmt) {
 	return id.Value, memory.Value, syscalls.Value, []Stmt{prologStmt, epilogStmt}
 }
 
+// sandboxPragma holds the arguments of a //go:sandbox directive until the
+// function declaration it applies to is parsed.
+type sandboxPragma struct {
+	pos  Pos
+	args []*BasicLit
+}
+
+// sandboxDirective parses a //go:sandbox ["name"] "mem" "sys" directive.
+// The text starts at pos and includes the "go:" prefix.
+func (p *parser) sandboxDirective(pos Pos, text string) {
+	if p.sbpragma != nil {
+		p.errorAt(pos, "duplicate //go:sandbox directive")
+		return
+	}
+	var args []*BasicLit
+	for i := len("go:sandbox"); i < len(text); {
+		if text[i] == ' ' || text[i] == '\t' {
+			i++
+			continue
+		}
+		j := i + 1
+		if text[i] == '"' {
+			for j < len(text) && text[j] != '"' {
+				if text[j] == '\\' {
+					j++
+				}
+				j++
+			}
+		}
+		if text[i] != '"' || j >= len(text) {
+			p.errorAt(pos, `usage: //go:sandbox ["name"] "mem" "sys"`)
+			return
+		}
+		j++
+		b := new(BasicLit)
+		b.pos = MakePos(pos.Base(), pos.Line(), pos.Col()+uint(i))
+		b.Kind = StringLit
+		b.Value = text[i:j]
+		args = append(args, b)
+		i = j
+	}
+	if len(args) != 2 && len(args) != 3 {
+		p.errorAt(pos, `usage: //go:sandbox ["name"] "mem" "sys"`)
+		return
+	}
+	p.sbpragma = &sandboxPragma{pos, args}
+}
+
+// sandboxDecl turns the function declaration f into a sandbox, as requested
+// by the //go:sandbox directive sb.
+func (p *parser) sandboxDecl(f *FuncDecl, sb *sandboxPragma) {
+	switch {
+	case f.Recv != nil:
+		p.errorAt(sb.pos, "//go:sandbox cannot be applied to methods")
+		return
+	case f.Body == nil:
+		p.errorAt(sb.pos, "//go:sandbox requires a function body")
+		return
+	}
+	var name *BasicLit
+	args := sb.args
+	if len(args) == 3 {
+		name, args = args[0], args[1:]
+	}
+	id, mem, sys, stmts := p.sandboxStmts(sb.pos, name, args[0], args[1])
+	f.Body.List = append(stmts, f.Body.List...)
+	f.IsSandbox = true
+	f.Id, f.Mem, f.Sys = id, mem, sys
+}
+
+// sandboxLiteral parses one of the configuration strings of a sandbox.
+func (p *parser) sandboxLiteral() *BasicLit {
+	pos := p.pos()
+	if b := p.oliteral(); b != nil {
+		return b
+	}
+	p.syntaxError("expecting string literal in sandbox configuration")
+	b := new(BasicLit)
+	b.pos = pos
+	b.Kind = StringLit
+	b.Value = `""`
+	b.Bad = true
+	return b
+}
+
+// sandboxEntry is an element of a comma-separated sandbox configuration,
+// along with its position in the source.
+type sandboxEntry struct {
+	value string
+	pos   Pos
+}
+
+// sandboxEntries splits the configuration string lit into its entries.
+// Entries are located precisely whenever the literal spans a single line
+// and does not contain escape sequences, otherwise they get the literal's
+// position.
+func (p *parser) sandboxEntries(lit *BasicLit, delim string) []sandboxEntry {
+	if lit.Bad {
+		return nil
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if lit.Kind != StringLit || err != nil {
+		p.errorAt(lit.pos, "sandbox configuration must be a string literal")
+		return nil
+	}
+	if len(value) == 0 {
+		return nil
+	}
+	exact := value == lit.Value[1:len(lit.Value)-1] && !strings.Contains(value, "\n")
+	var entries []sandboxEntry
+	off := uint(1)
+	for _, v := range strings.Split(value, delim) {
+		pos := lit.pos
+		if exact {
+			pos = MakePos(lit.pos.Base(), lit.pos.Line(), lit.pos.Col()+off)
+		}
+		entries = append(entries, sandboxEntry{v, pos})
+		off += uint(len(v) + len(delim))
+	}
+	return entries
+}
+
+// checkMemoryView reports malformed entries, duplicated packages and
+// packages that cannot be imported in the memory view of a sandbox.
+func (p *parser) checkMemoryView(lit *BasicLit) {
+	seen := make(map[string]bool)
+	for _, e := range p.sandboxEntries(lit, commons.DELIMITER_PKGS) {
+		if strings.TrimSpace(e.value) == "" {
+			p.errorAt(e.pos, "empty entry in sandbox memory view")
+			continue
+		}
+		if _, _, err := commons.ParseMemoryView(e.value); err != nil {
+			p.errorAt(e.pos, "invalid sandbox memory view entry "+strconv.Quote(e.value)+": "+strings.TrimSpace(err.Error()))
+			continue
+		}
+		name := strings.TrimSpace(strings.Split(e.value, commons.DELIMITER_ENTRY)[0])
+		if seen[name] {
+			p.errorAt(e.pos, "duplicate entry for "+name+" in sandbox memory view")
+			continue
+		}
+		seen[name] = true
+		if name != commons.SELF_IDENTIFIER && !canImportView(name) {
+			p.errorAt(e.pos, "sandbox memory view refers to "+name+", which cannot be imported from "+ImportPath)
+		}
+	}
+}
+
+// checkSyscalls reports unknown and duplicated syscall classes.
+func (p *parser) checkSyscalls(lit *BasicLit) {
+	seen := make(map[string]bool)
+	for _, e := range p.sandboxEntries(lit, commons.DELIMITER_SYSCLASS) {
+		if _, ok := commons.SyscallConfigs[e.value]; !ok {
+			p.errorAt(e.pos, "unknown syscall class "+strconv.Quote(e.value))
+			continue
+		}
+		if seen[e.value] {
+			p.errorAt(e.pos, "duplicate syscall class "+e.value)
+			continue
+		}
+		seen[e.value] = true
+	}
+}
+
+// canImportView reports whether the package being compiled is allowed to
+// import path, following the rules for internal packages.
+func canImportView(path string) bool {
+	if ImportPath == "" {
+		return true
+	}
+	return commons.CanImport(ImportPath, path, CompilingStd)
+}
+
 func sandboxGenerateCall(name string, args []Expr) *CallExpr {
 	pname := new(SBInternal)
 	pname.Value = name
@@ -0,0 +1,201 @@
+package syntax
+
+import (
+	"strings"
+	"testing"
+)
+
+// parseSandboxes parses src and returns the sandbox literals assigned at the
+// top level of its function bodies, along with the errors reported by the parser.
+func parseSandboxes(t *testing.T, src string) ([]*FuncLit, []error) {
+	var errs []error
+	errh := func(err error) { errs = append(errs, err) }
+	f, _ := Parse(NewFileBase("gosb.go"), strings.NewReader(src), errh, nil, 0)
+	var sbs []*FuncLit
+	if f == nil {
+		return nil, errs
+	}
+	for _, d := range f.DeclList {
+		fd, ok := d.(*FuncDecl)
+		if !ok || fd.Body == nil {
+			continue
+		}
+		for _, s := range fd.Body.List {
+			as, ok := s.(*AssignStmt)
+			if !ok {
+				continue
+			}
+			if lit, ok := as.Rhs.(*FuncLit); ok && lit.IsSandbox {
+				sbs = append(sbs, lit)
+			}
+		}
+	}
+	return sbs, errs
+}
+
+func TestSandboxName(t *testing.T) {
+	const src = `package p
+func f() {
+	a := sandbox "imgdecode" ["main:R", "io"]() {}
+	b := sandbox ["main:R", "io"]() {}
+	a()
+	b()
+}`
+	sbs, errs := parseSandboxes(t, src)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(sbs) != 2 {
+		t.Fatalf("got %d sandboxes, want 2", len(sbs))
+	}
+	if sbs[0].Id != `"imgdecode"` {
+		t.Errorf("got id %s, want %q", sbs[0].Id, "imgdecode")
+	}
+	if !strings.Contains(sbs[1].Id, ":") {
+		t.Errorf("got id %s, want a generated id", sbs[1].Id)
+	}
+}
+
+func TestSandboxBadName(t *testing.T) {
+	for _, name := range []string{`""`, `"-1"`, `"1:0"`, `"a b"`, `42`} {
+		src := "package p\nfunc f() { _ = sandbox " + name + ` ["", ""]() {} }`
+		_, errs := parseSandboxes(t, src)
+		if len(errs) == 0 {
+			t.Errorf("missing error for sandbox name %s", name)
+		}
+	}
+}
+
+func TestSandboxConfigErrors(t *testing.T) {
+	defer func(path string) { ImportPath = path }(ImportPath)
+	ImportPath = "example.com/a"
+	tests := []struct {
+		config string
+		err    string
+	}{
+		{`["main:R,fmt:Z", ""]`, "gosb.go:2:33: invalid sandbox memory view entry"},
+		{`["main:R,main:RW", ""]`, "gosb.go:2:33: duplicate entry for main"},
+		{`["main:R,", ""]`, "gosb.go:2:33: empty entry"},
+		{`["example.com/b/internal/c:R", ""]`, "gosb.go:2:26: sandbox memory view refers to example.com/b/internal/c"},
+		{`["internal/cpu:R", ""]`, "gosb.go:2:26: sandbox memory view refers to internal/cpu"},
+		{`["", "io,fiel"]`, "gosb.go:2:33: unknown syscall class"},
+		{`["", "io,io"]`, "gosb.go:2:33: duplicate syscall class io"},
+		{`[main, ""]`, "expecting string literal"},
+	}
+	for _, test := range tests {
+		src := "package p\nfunc f() { _ = sandbox " + test.config + `() {} }`
+		_, errs := parseSandboxes(t, src)
+		if len(errs) == 0 {
+			t.Errorf("%s: missing error", test.config)
+			continue
+		}
+		if !strings.Contains(errs[0].Error(), test.err) {
+			t.Errorf("%s: got %v, want %s", test.config, errs[0], test.err)
+		}
+	}
+
+	valid := []string{
+		`["main:RW,example.com/a/internal/b:R,self:P", "io,file"]`,
+		`["", ""]`,
+	}
+	for _, config := range valid {
+		src := "package p\nfunc f() { _ = sandbox " + config + `() {} }`
+		if _, errs := parseSandboxes(t, src); len(errs) != 0 {
+			t.Errorf("%s: unexpected errors %v", config, errs)
+		}
+	}
+}
+
+func TestSandboxDirective(t *testing.T) {
+	const src = `package p
+
+//go:sandbox "main:R" "io"
+func f() {}
+
+//go:sandbox "decode" "" ""
+func g() { f() }
+
+func h() {}
+`
+	var errs []error
+	errh := func(err error) { errs = append(errs, err) }
+	file, _ := Parse(NewFileBase("gosb.go"), strings.NewReader(src), errh, nil, 0)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	want := []struct {
+		isSandbox bool
+		id        string
+		stmts     int
+	}{
+		{true, "", 1},
+		{true, `"decode"`, 2},
+		{false, "", 0},
+	}
+	for i, d := range file.DeclList {
+		fd := d.(*FuncDecl)
+		if fd.IsSandbox != want[i].isSandbox || len(fd.Body.List) != want[i].stmts {
+			t.Errorf("%s: got sandbox %v with %d statements, want %v with %d", fd.Name.Value, fd.IsSandbox, len(fd.Body.List), want[i].isSandbox, want[i].stmts)
+		}
+		if want[i].id != "" && fd.Id != want[i].id {
+			t.Errorf("%s: got id %s, want %s", fd.Name.Value, fd.Id, want[i].id)
+		}
+	}
+	if !strings.Contains(file.DeclList[0].(*FuncDecl).Id, ":") {
+		t.Errorf("f: got id %s, want a generated id", file.DeclList[0].(*FuncDecl).Id)
+	}
+}
+
+func TestSandboxDirectiveErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		err string
+	}{
+		{"//go:sandbox \"main:R\"\nfunc f() {}", "usage: //go:sandbox"},
+		{"//go:sandbox main:R io\nfunc f() {}", "usage: //go:sandbox"},
+		{"//go:sandbox \"main:R\" \"io\nfunc f() {}", "usage: //go:sandbox"},
+		{"//go:sandbox \"main:Z\" \"io\"\nfunc f() {}", "gosb.go:2:15: invalid sandbox memory view entry"},
+		{"//go:sandbox \"\" \"\"\nfunc (T) f() {}", "cannot be applied to methods"},
+		{"//go:sandbox \"\" \"\"\nfunc f()", "requires a function body"},
+		{"//go:sandbox \"\" \"\"\nvar x int", "misplaced //go:sandbox directive"},
+		{"func f() {\n//go:sandbox \"\" \"\"\n}", "misplaced //go:sandbox directive"},
+		{"//go:sandbox \"\" \"\"\n//go:sandbox \"\" \"\"\nfunc f() {}", "duplicate //go:sandbox directive"},
+	}
+	for _, test := range tests {
+		var errs []error
+		errh := func(err error) { errs = append(errs, err) }
+		Parse(NewFileBase("gosb.go"), strings.NewReader("package p\n"+test.src), errh, nil, 0)
+		if len(errs) == 0 {
+			t.Errorf("%q: missing error", test.src)
+			continue
+		}
+		if !strings.Contains(errs[0].Error(), test.err) {
+			t.Errorf("%q: got %v, want %s", test.src, errs[0], test.err)
+		}
+	}
+}
+
+func TestSandboxEpilog(t *testing.T) {
+	const src = `package p
+func f() {
+	a := sandbox ["main:R", "io"]() {}
+	a()
+}`
+	defer func(old bool) { SandboxDefer = old }(SandboxDefer)
+	for _, deferred := range []bool{false, true} {
+		SandboxDefer = deferred
+		sbs, errs := parseSandboxes(t, src)
+		if len(errs) != 0 || len(sbs) != 1 {
+			t.Fatalf("got %d sandboxes and errors %v, want 1 sandbox", len(sbs), errs)
+		}
+		var epilog bool
+		for _, s := range sbs[0].Body.List {
+			if c, ok := s.(*CallStmt); ok && c.Tok == _Defer {
+				epilog = c.Call.Fun.(*SBInternal).Value == "sandbox_epilog"
+			}
+		}
+		if epilog != deferred {
+			t.Errorf("SandboxDefer=%v: got deferred epilog %v, want %v", deferred, epilog, deferred)
+		}
+	}
+}
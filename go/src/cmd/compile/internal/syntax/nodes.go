@@ -101,6 +101,11 @@ type (
 		Type   *FuncType
 		Body   *BlockStmt // nil means no body (forward declaration)
 		Pragma Pragma     // TODO(mdempsky): Cleaner solution.
+		// @aghosn values for a //go:sandbox function
+		IsSandbox bool
+		Id        string
+		Mem       string
+		Sys       string
 		decl
 	}
 )
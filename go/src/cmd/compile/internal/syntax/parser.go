@@ -25,6 +25,8 @@ type parser struct {
 	errcnt int      // number of errors encountered
 	pragma Pragma   // pragma flags
 
+	sbpragma *sandboxPragma // pending //go:sandbox directive
+
 	fnest  int    // function nesting level (for error handling)
 	xnest  int    // expression nesting level (for complit ambiguity resolution)
 	indent []byte // tracing support
@@ -65,6 +67,12 @@ func (p *parser) init(file *PosBase, r io.Reader, errh ErrorHandler, pragh Pragm
 				return
 			}
 
+			// @aghosn //go:sandbox directives are handled by the parser itself.
+			if text == "go:sandbox" || strings.HasPrefix(text, "go:sandbox ") {
+				p.sandboxDirective(p.posAt(line, col+2), text) // +2 to skip over // or /*
+				return
+			}
+
 			// go: directive (but be conservative and test)
 			if pragh != nil && strings.HasPrefix(text, "go:") {
 				p.pragma |= pragh(p.posAt(line, col+2), text) // +2 to skip over // or /*
@@ -77,6 +85,7 @@ func (p *parser) init(file *PosBase, r io.Reader, errh ErrorHandler, pragh Pragm
 	p.first = nil
 	p.errcnt = 0
 	p.pragma = 0
+	p.sbpragma = nil
 
 	p.fnest = 0
 	p.xnest = 0
@@ -415,6 +424,10 @@ func (p *parser) fileOrNil() *File {
 		// Reset p.pragma BEFORE advancing to the next token (consuming ';')
 		// since comments before may set pragmas for the next function decl.
 		p.pragma = 0
+		if p.sbpragma != nil {
+			p.errorAt(p.sbpragma.pos, "misplaced //go:sandbox directive")
+			p.sbpragma = nil
+		}
 
 		if p.tok != _EOF && !p.got(_Semi) {
 			p.syntaxError("after top level declaration")
@@ -600,6 +613,10 @@ func (p *parser) funcDeclOrNil() *FuncDecl {
 	f := new(FuncDecl)
 	f.pos = p.pos()
 
+	// @aghosn directives within the body do not apply to this function.
+	sb := p.sbpragma
+	p.sbpragma = nil
+
 	if p.tok == _Lparen {
 		rcvr := p.paramList()
 		switch len(rcvr) {
@@ -625,6 +642,9 @@ func (p *parser) funcDeclOrNil() *FuncDecl {
 		f.Body = p.funcBody()
 	}
 	f.Pragma = p.pragma
+	if sb != nil {
+		p.sandboxDecl(f, sb)
+	}
 
 	return f
 }
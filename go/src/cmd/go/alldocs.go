@@ -26,6 +26,7 @@
 // 	list        list packages or modules
 // 	mod         module maintenance
 // 	run         compile and run Go program
+// 	sandbox     sandbox maintenance
 // 	test        test packages
 // 	tool        run specified go tool
 // 	version     print Go version
@@ -788,6 +789,9 @@
 //         TestImports  []string          // imports from TestGoFiles
 //         XTestImports []string          // imports from XTestGoFiles
 //
+//         // Sandbox information
+//         Sandboxes []*Sandbox // sandboxes defined in GoFiles and CgoFiles
+//
 //         // Error information
 //         Incomplete bool            // this package or a dependency has an error
 //         Error      *PackageError   // error loading package
@@ -808,6 +812,16 @@
 //         Err           string   // the error itself
 //     }
 //
+// The sandbox information describes the sandbox literals and the functions
+// marked with //go:sandbox directives:
+//
+//     type Sandbox struct {
+//         Id   string // name of the sandbox, empty if the compiler generates its id
+//         Func string // function that implements the sandbox, as named by the compiler
+//         Mem  string // memory view
+//         Sys  string // syscall classes
+//     }
+//
 // The module information is a Module struct, defined in the discussion
 // of list -m below.
 //
@@ -1253,6 +1267,65 @@
 // See also: go build.
 //
 //
+// Sandbox maintenance
+//
+// Go sandbox provides access to the sandbox information that the linker
+// records in executables built with sandboxes.
+//
+// Usage:
+//
+// 	go sandbox <command> [arguments]
+//
+// The commands are:
+//
+// 	diff        report privileges gained by sandboxes
+// 	inspect     print the sandboxes of executables
+//
+// Use "go help sandbox <command>" for more information about a command.
+//
+// Report privileges gained by sandboxes
+//
+// Usage:
+//
+// 	go sandbox diff [old new]
+//
+// Diff compares the sandboxes of two executables and reports the privileges
+// that changed between old and new, for instance after a dependency update.
+//
+// Sandboxes are matched by id and, failing that, by function name. For each
+// pair, diff reports the packages added to or removed from the sandbox, the
+// packages whose permissions in the memory view were upgraded, and the system
+// calls the sandbox can newly perform. It also reports the sandboxes that only
+// appear in one of the executables.
+//
+// Diff exits with a non-zero status when privileges grew: a sandbox gained a
+// package, a permission or a system call, or a sandbox of old disappeared and
+// its code now runs unrestricted.
+//
+//
+// Print the sandboxes of executables
+//
+// Usage:
+//
+// 	go sandbox inspect [-json] file...
+//
+// Inspect prints the sandbox information that the linker recorded in the named
+// executables: the address ranges of each bloated package, the guard pages
+// between them, and for each sandbox its id, function, memory view and allowed
+// system calls.
+//
+// The memory view lists the packages the sandbox can access, with the
+// permissions it requests, or "default" when it keeps the protections of the
+// package sections. System calls are described by their classes; calls outside
+// of any class are listed by number, as in #231. A sandbox without restrictions
+// allows "all" system calls.
+//
+// Addresses are the link-time ones, rounded to pages as gosb maps them. They
+// are relative to the load address for position independent executables.
+//
+// The -json flag prints the information as JSON instead, one object per file.
+//
+//
 // Test packages
 //
 // Usage:
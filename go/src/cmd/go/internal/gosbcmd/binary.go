@@ -0,0 +1,138 @@
+package gosbcmd
+
+import (
+	"debug/elf"
+	"errors"
+	"fmt"
+
+	"gosb/commons"
+)
+
+// binary is the sandbox information of an executable, as decoded by gosb at
+// startup.
+type binary struct {
+	Packages []*commons.Package
+	Guards   []commons.Guard
+	Domains  []*commons.SandboxDomain
+}
+
+// readBinary decodes the .bloated and .sandboxes sections of the executable
+// file. Addresses are the link-time ones.
+func readBinary(file string) (*binary, error) {
+	f, err := elf.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	bloated, err := sectionData(f, ".bloated")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	sandboxes, err := sectionData(f, ".sandboxes")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	bases, err := sectionBases(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+
+	b := &binary{}
+	if b.Packages, b.Guards, err = commons.DecodePackages(bloated, bases); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	domains, err := commons.DecodeDomains(sandboxes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	// The linker describes the code outside of the bloated packages as a
+	// domain with id -1, which is not a sandbox.
+	for _, d := range domains {
+		if d.Id != "-1" {
+			b.Domains = append(b.Domains, d)
+		}
+	}
+	return b, nil
+}
+
+func sectionData(f *elf.File, name string) ([]byte, error) {
+	s := f.Section(name)
+	if s == nil {
+		return nil, fmt.Errorf("no %s section, not built with sandboxes", name)
+	}
+	return s.Data()
+}
+
+// sectionBases returns the link-time addresses of commons.SectionBases, as
+// the linker stored them in runtime.gosbsections. The symbol table might be
+// stripped, the variable is found by its first words instead: the addresses
+// of the .bloated, .sandboxes and .gosbsyms sections.
+func sectionBases(f *elf.File) ([]uint64, error) {
+	var head []uint64
+	for _, name := range []string{".bloated", ".sandboxes", ".gosbsyms"} {
+		s := f.Section(name)
+		if s == nil {
+			return nil, fmt.Errorf("no %s section, not built with sandboxes", name)
+		}
+		head = append(head, s.Addr)
+	}
+	data, err := sectionData(f, ".noptrdata")
+	if err != nil {
+		return nil, err
+	}
+	word := 8
+	if f.Class == elf.ELFCLASS32 {
+		word = 4
+	}
+	at := func(i int) uint64 {
+		if word == 4 {
+			return uint64(f.ByteOrder.Uint32(data[i*word:]))
+		}
+		return f.ByteOrder.Uint64(data[i*word:])
+	}
+	n := len(head) + len(commons.SectionBases)
+search:
+	for i := 0; (i+n)*word <= len(data); i++ {
+		for j, v := range head {
+			if at(i+j) != v {
+				continue search
+			}
+		}
+		bases := make([]uint64, len(commons.SectionBases))
+		for j := range bases {
+			bases[j] = at(i + len(head) + j)
+		}
+		return bases, nil
+	}
+	return nil, errors.New("no runtime.gosbsections in .noptrdata")
+}
+
+// protString formats a protection as rwx, followed by a u for pages
+// accessible to the user, as in the -gosbmap of the linker.
+func protString(prot uint8) string {
+	b := []byte("---")
+	if prot&commons.R_VAL != 0 {
+		b[0] = 'r'
+	}
+	if prot&commons.W_VAL != 0 {
+		b[1] = 'w'
+	}
+	if prot&commons.X_VAL != 0 {
+		b[2] = 'x'
+	}
+	if prot&commons.USER_VAL != 0 {
+		b = append(b, 'u')
+	}
+	return string(b)
+}
+
+// syscallNames describes a syscall mask by its classes, followed by the
+// numbers of the syscalls outside of these classes.
+func syscallNames(mask commons.SyscallMask) []string {
+	classes, extras := commons.DecodeSyscalls(mask)
+	for _, e := range extras {
+		classes = append(classes, fmt.Sprintf("#%d", e))
+	}
+	return classes
+}
@@ -0,0 +1,191 @@
+// go sandbox diff
+
+package gosbcmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cmd/go/internal/base"
+
+	"gosb/commons"
+)
+
+var cmdDiff = &base.Command{
+	UsageLine: "go sandbox diff [old new]",
+	Short:     "report privileges gained by sandboxes",
+	Long: `
+Diff compares the sandboxes of two executables and reports the privileges
+that changed between old and new, for instance after a dependency update.
+
+Sandboxes are matched by id and, failing that, by function name. For each
+pair, diff reports the packages added to or removed from the sandbox, the
+packages whose permissions in the memory view were upgraded, and the system
+calls the sandbox can newly perform. It also reports the sandboxes that only
+appear in one of the executables.
+
+Diff exits with a non-zero status when privileges grew: a sandbox gained a
+package, a permission or a system call, or a sandbox of old disappeared and
+its code now runs unrestricted.
+	`,
+}
+
+func init() {
+	cmdDiff.Run = runDiff // break init cycle
+}
+
+func runDiff(cmd *base.Command, args []string) {
+	if len(args) != 2 {
+		base.Fatalf("usage: %s", cmd.UsageLine)
+	}
+	oldb, err := readBinary(args[0])
+	if err != nil {
+		base.Fatalf("go sandbox diff: %v", err)
+	}
+	newb, err := readBinary(args[1])
+	if err != nil {
+		base.Fatalf("go sandbox diff: %v", err)
+	}
+	grew := false
+	for _, d := range diffDomains(oldb.Domains, newb.Domains) {
+		fmt.Print(d)
+		grew = grew || d.grew()
+	}
+	if grew {
+		base.SetExitStatus(1)
+	}
+	base.ExitIfErrors()
+}
+
+// domainDiff is the difference between two matching sandboxes. Old or New
+// is nil for sandboxes that only appear in one of the executables.
+type domainDiff struct {
+	Old, New *commons.SandboxDomain
+	Added    []string // packages
+	Removed  []string // packages
+	Upgrades []string // package: old -> new permissions
+	Syscalls commons.SyscallMask
+}
+
+// grew reports whether the sandbox gained privileges.
+func (d *domainDiff) grew() bool {
+	if d.New == nil {
+		return true
+	}
+	return len(d.Added) > 0 || len(d.Upgrades) > 0 || d.Syscalls != commons.SyscallMask{}
+}
+
+func (d *domainDiff) String() string {
+	var b strings.Builder
+	switch {
+	case d.New == nil:
+		fmt.Fprintf(&b, "removed sandbox %s func=%s\n", d.Old.Id, d.Old.Func)
+		return b.String()
+	case d.Old == nil:
+		fmt.Fprintf(&b, "added sandbox %s func=%s\n", d.New.Id, d.New.Func)
+		return b.String()
+	}
+	if !d.grew() && len(d.Removed) == 0 {
+		return ""
+	}
+	fmt.Fprintf(&b, "sandbox %s func=%s\n", d.New.Id, d.New.Func)
+	for _, p := range d.Added {
+		fmt.Fprintf(&b, "\t+package %s\n", p)
+	}
+	for _, p := range d.Removed {
+		fmt.Fprintf(&b, "\t-package %s\n", p)
+	}
+	for _, u := range d.Upgrades {
+		fmt.Fprintf(&b, "\tview %s\n", u)
+	}
+	if d.Syscalls != (commons.SyscallMask{}) {
+		fmt.Fprintf(&b, "\t+syscalls %s\n", strings.Join(syscallNames(d.Syscalls), ","))
+	}
+	return b.String()
+}
+
+// diffDomains matches the sandboxes of olds and news, by id first and then by
+// function, and computes their differences. Sandboxes without a match are
+// returned with a nil Old or New.
+func diffDomains(olds, news []*commons.SandboxDomain) []*domainDiff {
+	var diffs []*domainDiff
+	matched := make(map[*commons.SandboxDomain]bool)
+	match := func(o *commons.SandboxDomain, key func(*commons.SandboxDomain) string) {
+		for _, n := range news {
+			if !matched[n] && key(n) == key(o) {
+				matched[o], matched[n] = true, true
+				diffs = append(diffs, diffDomain(o, n))
+				return
+			}
+		}
+	}
+	for _, o := range olds {
+		match(o, func(d *commons.SandboxDomain) string { return d.Id })
+	}
+	for _, o := range olds {
+		if !matched[o] {
+			match(o, func(d *commons.SandboxDomain) string { return d.Func })
+		}
+	}
+	for _, o := range olds {
+		if !matched[o] {
+			diffs = append(diffs, &domainDiff{Old: o})
+		}
+	}
+	for _, n := range news {
+		if !matched[n] {
+			diffs = append(diffs, &domainDiff{New: n})
+		}
+	}
+	return diffs
+}
+
+// diffDomain computes the privileges n gained or lost compared to o.
+// Packages outside of the memory view keep the protections of their
+// sections, the most permissive ones, as in globals.ComputeMemoryView.
+func diffDomain(o, n *commons.SandboxDomain) *domainDiff {
+	d := &domainDiff{Old: o, New: n}
+	inOld := make(map[string]bool)
+	for _, p := range o.Pkgs {
+		inOld[p] = true
+	}
+	inNew := make(map[string]bool)
+	for _, p := range n.Pkgs {
+		inNew[p] = true
+		if !inOld[p] {
+			d.Added = append(d.Added, p)
+			continue
+		}
+		op, np := viewPerm(o, p), viewPerm(n, p)
+		if np&^op != 0 {
+			d.Upgrades = append(d.Upgrades, fmt.Sprintf("%s: %s -> %s", p, permString(o, p), permString(n, p)))
+		}
+	}
+	for _, p := range o.Pkgs {
+		if !inNew[p] {
+			d.Removed = append(d.Removed, p)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Strings(d.Upgrades)
+	for i := range n.Sys {
+		d.Syscalls[i] = n.Sys[i] &^ o.Sys[i]
+	}
+	return d
+}
+
+func viewPerm(d *commons.SandboxDomain, pkg string) uint8 {
+	if prot, ok := d.View[pkg]; ok {
+		return prot
+	}
+	return commons.D_VAL
+}
+
+func permString(d *commons.SandboxDomain, pkg string) string {
+	if prot, ok := d.View[pkg]; ok {
+		return protString(prot)
+	}
+	return "default"
+}
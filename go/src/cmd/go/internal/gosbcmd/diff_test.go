@@ -0,0 +1,75 @@
+package gosbcmd
+
+import (
+	"reflect"
+	"testing"
+
+	"gosb/commons"
+)
+
+func TestDiffDomains(t *testing.T) {
+	net := commons.SyscallMask{1 << 41}
+	olds := []*commons.SandboxDomain{
+		{Id: "a", Func: "main.a", Pkgs: []string{"bytes", "main"}, View: map[string]uint8{"main": commons.R_VAL}},
+		{Id: "b", Func: "main.b", Pkgs: []string{"fmt"}},
+		{Id: "c", Func: "main.c", Pkgs: []string{"os"}},
+	}
+	news := []*commons.SandboxDomain{
+		{Id: "a", Func: "main.a", Pkgs: []string{"main", "net"}, View: map[string]uint8{"main": commons.R_VAL | commons.W_VAL}, Sys: net},
+		{Id: "renamed", Func: "main.b", Pkgs: []string{"fmt"}},
+		{Id: "d", Func: "main.d", Pkgs: []string{"io"}},
+	}
+	diffs := diffDomains(olds, news)
+	if len(diffs) != 4 {
+		t.Fatalf("diffDomains returned %d diffs, want 4", len(diffs))
+	}
+
+	a := diffs[0]
+	if a.Old != olds[0] || a.New != news[0] || !a.grew() {
+		t.Errorf("sandbox a: got %v -> %v, grew %v", a.Old, a.New, a.grew())
+	}
+	if !reflect.DeepEqual(a.Added, []string{"net"}) || !reflect.DeepEqual(a.Removed, []string{"bytes"}) {
+		t.Errorf("sandbox a: added %v, removed %v", a.Added, a.Removed)
+	}
+	if !reflect.DeepEqual(a.Upgrades, []string{"main: r-- -> rw-"}) {
+		t.Errorf("sandbox a: upgrades %v", a.Upgrades)
+	}
+	if a.Syscalls != net {
+		t.Errorf("sandbox a: syscalls %v, want %v", a.Syscalls, net)
+	}
+
+	if b := diffs[1]; b.Old != olds[1] || b.New != news[1] || b.grew() || b.String() != "" {
+		t.Errorf("sandbox b: not matched by function, or reported %q", b.String())
+	}
+	if c := diffs[2]; c.Old != olds[2] || c.New != nil || !c.grew() {
+		t.Errorf("removed sandbox c: got %v -> %v, grew %v", c.Old, c.New, c.grew())
+	}
+	if d := diffs[3]; d.Old != nil || d.New != news[2] || d.grew() {
+		t.Errorf("added sandbox d: got %v -> %v, grew %v", d.Old, d.New, d.grew())
+	}
+}
+
+func TestDiffDomainDefaultView(t *testing.T) {
+	o := &commons.SandboxDomain{Pkgs: []string{"main"}, View: map[string]uint8{"main": commons.R_VAL}}
+	n := &commons.SandboxDomain{Pkgs: []string{"main"}}
+	if d := diffDomain(o, n); !reflect.DeepEqual(d.Upgrades, []string{"main: r-- -> default"}) {
+		t.Errorf("dropping a view restriction: upgrades %v", d.Upgrades)
+	}
+	if d := diffDomain(n, o); d.grew() {
+		t.Errorf("adding a view restriction grew privileges: %v", d.Upgrades)
+	}
+}
+
+// The go command dispatches subcommands by the name of their usage line.
+func TestSubcommandNames(t *testing.T) {
+	want := map[string]bool{"diff": true, "inspect": true}
+	for _, c := range CmdSandbox.Commands {
+		if !want[c.Name()] {
+			t.Errorf("unexpected subcommand %q, usage line %q", c.Name(), c.UsageLine)
+		}
+		delete(want, c.Name())
+	}
+	for name := range want {
+		t.Errorf("go sandbox %s is not dispatched", name)
+	}
+}
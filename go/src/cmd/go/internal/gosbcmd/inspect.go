@@ -0,0 +1,168 @@
+// go sandbox inspect
+
+package gosbcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"cmd/go/internal/base"
+
+	"gosb/commons"
+)
+
+var cmdInspect = &base.Command{
+	UsageLine: "go sandbox inspect [-json] file...",
+	Short:     "print the sandboxes of executables",
+	Long: `
+Inspect prints the sandbox information that the linker recorded in the named
+executables: the address ranges of each bloated package, the guard pages
+between them, and for each sandbox its id, function, memory view and allowed
+system calls.
+
+The memory view lists the packages the sandbox can access, with the
+permissions it requests, or "default" when it keeps the protections of the
+package sections. System calls are described by their classes; calls outside
+of any class are listed by number, as in #231. A sandbox without restrictions
+allows "all" system calls.
+
+Addresses are the link-time ones, rounded to pages as gosb maps them. They
+are relative to the load address for position independent executables.
+
+The -json flag prints the information as JSON instead, one object per file.
+	`,
+}
+
+var inspectJSON = cmdInspect.Flag.Bool("json", false, "")
+
+func init() {
+	cmdInspect.Run = runInspect // break init cycle
+}
+
+// Inspection is the information printed by go sandbox inspect -json.
+type Inspection struct {
+	File      string
+	Packages  []InspectPackage
+	Guards    []InspectGuard `json:",omitempty"`
+	Sandboxes []InspectSandbox
+}
+
+type InspectPackage struct {
+	Name     string
+	Id       int
+	Sections []InspectSection `json:",omitempty"`
+}
+
+type InspectSection struct {
+	Start uint64
+	End   uint64
+	Prot  string
+}
+
+type InspectGuard struct {
+	Start  uint64
+	End    uint64
+	Before string
+	After  string
+}
+
+type InspectSandbox struct {
+	Id       string
+	Func     string
+	Pristine bool              `json:",omitempty"`
+	View     map[string]string // package to permissions
+	Syscalls []string
+}
+
+func runInspect(cmd *base.Command, args []string) {
+	if len(args) == 0 {
+		base.Fatalf("go sandbox inspect: no files")
+	}
+	for _, file := range args {
+		b, err := readBinary(file)
+		if err != nil {
+			base.Errorf("go sandbox inspect: %v", err)
+			continue
+		}
+		in := inspect(file, b)
+		if *inspectJSON {
+			data, err := json.MarshalIndent(in, "", "\t")
+			if err != nil {
+				base.Fatalf("go sandbox inspect: %v", err)
+			}
+			os.Stdout.Write(append(data, '\n'))
+			continue
+		}
+		printInspection(in)
+	}
+	base.ExitIfErrors()
+}
+
+// inspect describes the sandbox information b of file.
+func inspect(file string, b *binary) *Inspection {
+	in := &Inspection{File: file}
+	for _, p := range b.Packages {
+		ip := InspectPackage{Name: p.Name, Id: p.Id}
+		for _, s := range p.Sects {
+			if s.Size == 0 {
+				continue
+			}
+			start := commons.Round(s.Addr, false)
+			ip.Sections = append(ip.Sections, InspectSection{start, start + commons.Round(s.Size, true), protString(s.Prot)})
+		}
+		in.Packages = append(in.Packages, ip)
+	}
+	sort.Slice(in.Packages, func(i, j int) bool {
+		return in.Packages[i].Name < in.Packages[j].Name
+	})
+	for _, g := range b.Guards {
+		in.Guards = append(in.Guards, InspectGuard{g.Addr, g.Addr + g.Size, g.Before, g.After})
+	}
+	for _, d := range b.Domains {
+		is := InspectSandbox{Id: d.Id, Func: d.Func, Pristine: d.Pristine, View: make(map[string]string)}
+		for _, p := range d.Pkgs {
+			is.View[p] = "default"
+			if prot, ok := d.View[p]; ok {
+				is.View[p] = protString(prot)
+			}
+		}
+		is.Syscalls = syscallNames(d.Sys)
+		in.Sandboxes = append(in.Sandboxes, is)
+	}
+	return in
+}
+
+func printInspection(in *Inspection) {
+	fmt.Printf("%s\n", in.File)
+	fmt.Printf("packages\n")
+	for _, p := range in.Packages {
+		fmt.Printf("\t%s id=%d\n", p.Name, p.Id)
+		for _, s := range p.Sections {
+			fmt.Printf("\t\t[%#x, %#x) %s\n", s.Start, s.End, s.Prot)
+		}
+	}
+	if len(in.Guards) > 0 {
+		fmt.Printf("guards\n")
+		for _, g := range in.Guards {
+			fmt.Printf("\t[%#x, %#x) %s | %s\n", g.Start, g.End, g.Before, g.After)
+		}
+	}
+	fmt.Printf("sandboxes\n")
+	for _, s := range in.Sandboxes {
+		fmt.Printf("\t%s func=%s", s.Id, s.Func)
+		if s.Pristine {
+			fmt.Printf(" pristine")
+		}
+		fmt.Printf("\n")
+		view := make([]string, 0, len(s.View))
+		for p, prot := range s.View {
+			view = append(view, p+":"+prot)
+		}
+		sort.Strings(view)
+		fmt.Printf("\t\tview %s\n", strings.Join(view, " "))
+		fmt.Printf("\t\tsyscalls %s\n", strings.Join(s.Syscalls, ","))
+	}
+}
@@ -0,0 +1,17 @@
+// Package gosbcmd implements the ``go sandbox'' command.
+package gosbcmd
+
+import "cmd/go/internal/base"
+
+var CmdSandbox = &base.Command{
+	UsageLine: "go sandbox",
+	Short:     "sandbox maintenance",
+	Long: `Go sandbox provides access to the sandbox information that the linker
+records in executables built with sandboxes.
+	`,
+
+	Commands: []*base.Command{
+		cmdDiff,
+		cmdInspect,
+	},
+}
@@ -99,6 +99,9 @@ to -f '{{.ImportPath}}'. The struct being passed to the template is:
         TestImports  []string          // imports from TestGoFiles
         XTestImports []string          // imports from XTestGoFiles
 
+        // Sandbox information
+        Sandboxes []*Sandbox // sandboxes defined in GoFiles and CgoFiles
+
         // Error information
         Incomplete bool            // this package or a dependency has an error
         Error      *PackageError   // error loading package
@@ -119,6 +122,16 @@ The error information, if any, is
         Err           string   // the error itself
     }
 
+The sandbox information describes the sandbox literals and the functions
+marked with //go:sandbox directives:
+
+    type Sandbox struct {
+        Id   string // name of the sandbox, empty if the compiler generates its id
+        Func string // function that implements the sandbox, as named by the compiler
+        Mem  string // memory view
+        Sys  string // syscall classes
+    }
+
 The module information is a Module struct, defined in the discussion
 of list -m below.
 
@@ -502,6 +515,7 @@ func runList(cmd *base.Command, args []string) {
 
 	// Do we need to run a build to gather information?
 	needStale := *listJson || strings.Contains(*listFmt, ".Stale")
+	needSandboxes := *listJson || strings.Contains(*listFmt, ".Sandboxes")
 	if needStale || *listExport || *listCompiled {
 		var b work.Builder
 		b.Init()
@@ -527,6 +541,9 @@ func runList(cmd *base.Command, args []string) {
 		if *listCompiled {
 			p.Imports = str.StringList(p.Imports, p.Internal.CompiledImports)
 		}
+		if needSandboxes {
+			p.Sandboxes = load.Sandboxes(p)
+		}
 	}
 
 	if *listTest {
@@ -0,0 +1,221 @@
+package load
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"cmd/go/internal/str"
+)
+
+// Sandbox describes a sandbox defined in the sources of a package, either
+// by a sandbox literal or by a //go:sandbox directive.
+type Sandbox struct {
+	Id   string `json:",omitempty"` // name of the sandbox, empty if the compiler generates its id
+	Func string // function that implements the sandbox, as named by the compiler
+	Mem  string // memory view
+	Sys  string // syscall classes
+}
+
+// Sandboxes returns the sandboxes defined in the Go files of p.
+// Files that cannot be parsed are skipped, the compiler reports their errors.
+func Sandboxes(p *Package) []*Sandbox {
+	files := str.StringList(p.GoFiles, p.CgoFiles)
+	srcs := make([][]byte, len(files))
+	found := false
+	for i, file := range files {
+		if !filepath.IsAbs(file) {
+			files[i] = filepath.Join(p.Dir, file)
+		}
+		srcs[i], _ = ioutil.ReadFile(files[i])
+		found = found || bytes.Contains(srcs[i], []byte("sandbox"))
+	}
+	if !found {
+		return nil
+	}
+
+	pkgpath := p.ImportPath
+	if p.Name == "main" && !p.Internal.ForceLibrary {
+		pkgpath = "main"
+	}
+	s := &sandboxScanner{pkgpath: pkgpath}
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for i, src := range srcs {
+		// The files without sandboxes count, as they also name closures.
+		if f, err := parser.ParseFile(fset, files[i], src, parser.ParseComments); err == nil {
+			parsed = append(parsed, f)
+		}
+	}
+	// The compiler names the closures of package-level variables before
+	// the ones of functions.
+	for _, f := range parsed {
+		for _, d := range f.Decls {
+			if d, ok := d.(*ast.GenDecl); ok {
+				s.closures(d, "glob.", &s.globgen, false)
+			}
+		}
+	}
+	for _, f := range parsed {
+		s.funcs(f)
+	}
+	return s.sandboxes
+}
+
+// sandboxScanner finds the sandboxes of a package and names them the way the
+// compiler does.
+type sandboxScanner struct {
+	pkgpath   string
+	sandboxes []*Sandbox
+	globgen   int // closures of package-level variables and of _ functions
+	initgen   int // init functions
+}
+
+// sandboxDirective parses the arguments of a //go:sandbox directive.
+func sandboxDirective(text string) *Sandbox {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", -1, len(text))
+	var sc scanner.Scanner
+	sc.Init(file, []byte(text), nil, 0)
+	var args []string
+	for {
+		_, t, lit := sc.Scan()
+		if t == token.EOF || t == token.SEMICOLON && lit == "\n" {
+			break
+		}
+		if t != token.STRING {
+			return nil
+		}
+		args = append(args, lit)
+	}
+	return sandboxConfig(args)
+}
+
+// sandboxLiteral returns the configuration of the sandbox literal lit.
+func sandboxLiteral(lit *ast.SandboxLit) *Sandbox {
+	var args []string
+	for _, l := range []*ast.BasicLit{lit.Name, lit.Mem, lit.Sys} {
+		if l != nil {
+			args = append(args, l.Value)
+		}
+	}
+	return sandboxConfig(args)
+}
+
+// sandboxConfig unquotes the optional name, the memory view and the syscall
+// classes of a sandbox.
+func sandboxConfig(args []string) *Sandbox {
+	if len(args) != 2 && len(args) != 3 {
+		return nil
+	}
+	values := make([]string, len(args))
+	for i, a := range args {
+		v, err := strconv.Unquote(a)
+		if err != nil {
+			return nil
+		}
+		values[i] = v
+	}
+	sb := &Sandbox{}
+	if len(values) == 3 {
+		sb.Id, values = values[0], values[1:]
+	}
+	sb.Mem, sb.Sys = values[0], values[1]
+	return sb
+}
+
+// funcs records the sandboxes declared by //go:sandbox directives and the
+// sandbox literals of the functions of f.
+func (s *sandboxScanner) funcs(f *ast.File) {
+	prev := f.Name.End()
+	comments := f.Comments
+	for _, decl := range f.Decls {
+		d, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			prev = decl.End()
+			continue
+		}
+		name := d.Name.Name
+		if d.Recv != nil && len(d.Recv.List) == 1 {
+			name = recvString(d.Recv.List[0].Type) + "." + name
+		} else if name == "init" {
+			name = fmt.Sprintf("init.%d", s.initgen)
+			s.initgen++
+		}
+		// A directive applies to the declaration that follows it.
+		for ; len(comments) > 0 && comments[0].Pos() < d.Pos(); comments = comments[1:] {
+			if comments[0].Pos() < prev || d.Recv != nil || d.Body == nil {
+				continue
+			}
+			for _, c := range comments[0].List {
+				if !strings.HasPrefix(c.Text, "//go:sandbox ") {
+					continue
+				}
+				if sb := sandboxDirective(c.Text[len("//go:sandbox"):]); sb != nil {
+					sb.Func = s.pkgpath + "." + name
+					s.sandboxes = append(s.sandboxes, sb)
+				}
+			}
+		}
+		prev = d.End()
+		if d.Body == nil {
+			continue
+		}
+		gen := new(int)
+		if name == "_" {
+			gen = &s.globgen
+		}
+		s.closures(d.Body, name, gen, false)
+	}
+}
+
+// closures names the function and sandbox literals of n, the body of the
+// function outer, and records the sandboxes. Closures are numbered in the
+// order of the source, through gen, as in the compiler's closurename.
+func (s *sandboxScanner) closures(n ast.Node, outer string, gen *int, inClosure bool) {
+	ast.Inspect(n, func(n ast.Node) bool {
+		var fn *ast.FuncLit
+		var sb *Sandbox
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			fn = n
+		case *ast.SandboxLit:
+			fn, sb = n.Func, sandboxLiteral(n)
+		default:
+			return true
+		}
+		prefix := "func"
+		if inClosure {
+			prefix = ""
+		}
+		*gen++
+		name := fmt.Sprintf("%s.%s%d", outer, prefix, *gen)
+		if sb != nil {
+			sb.Func = s.pkgpath + "." + name
+			s.sandboxes = append(s.sandboxes, sb)
+		}
+		s.closures(fn.Body, name, new(int), true)
+		return false
+	})
+}
+
+// recvString formats the receiver type of a method as in the names of
+// methods, T or (*T).
+func recvString(x ast.Expr) string {
+	switch x := x.(type) {
+	case *ast.ParenExpr:
+		return recvString(x.X)
+	case *ast.StarExpr:
+		return "(*" + recvString(x.X) + ")"
+	case *ast.Ident:
+		return x.Name
+	}
+	return "?"
+}
@@ -0,0 +1,83 @@
+package load
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const sandboxSrcA = `package p
+
+import "fmt"
+
+var f = func() {}
+
+func init() {}
+
+//go:sandbox "decl" "fmt:R" "file"
+func Decl() {
+	fmt.Println("decl")
+}
+
+func (t *T) M() {
+	g := func() {
+		sandbox ["", "net"] () {}()
+	}
+	g()
+}
+`
+
+const sandboxSrcB = `package p
+
+type T struct{}
+
+func init() {
+	sandbox "named" ["fmt:RW", "file,net"] () {
+		func() {}()
+	}()
+}
+
+//go:sandbox "" "net"
+func _() {}
+`
+
+func TestSandboxes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gosb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "a.go"), []byte(sandboxSrcA), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "b.go"), []byte(sandboxSrcB), 0666); err != nil {
+		t.Fatal(err)
+	}
+	p := &Package{PackagePublic: PackagePublic{
+		Dir:        dir,
+		ImportPath: "example.com/p",
+		Name:       "p",
+		GoFiles:    []string{"a.go", "b.go"},
+	}}
+	want := []*Sandbox{
+		{Id: "decl", Func: "example.com/p.Decl", Mem: "fmt:R", Sys: "file"},
+		{Func: "example.com/p.(*T).M.func1.1", Mem: "", Sys: "net"},
+		{Id: "named", Func: "example.com/p.init.1.func1", Mem: "fmt:RW", Sys: "file,net"},
+		{Func: "example.com/p._", Mem: "", Sys: "net"},
+	}
+	got := Sandboxes(p)
+	if !reflect.DeepEqual(got, want) {
+		for _, sb := range got {
+			t.Logf("got %+v", *sb)
+		}
+		t.Errorf("Sandboxes: wrong result")
+	}
+
+	p.GoFiles = []string{"b.go"}
+	p.Name = "main"
+	if got := Sandboxes(p); len(got) != 2 || got[0].Func != "main.init.0.func1" {
+		t.Errorf("Sandboxes of a main package: got %+v", got[0])
+	}
+}
@@ -113,6 +113,9 @@ type PackagePublic struct {
 	ImportMap map[string]string `json:",omitempty"` // map from source import to ImportPath (identity entries omitted)
 	Deps      []string          `json:",omitempty"` // all (recursively) imported dependencies
 
+	// Sandbox information, only initialized by the list command.
+	Sandboxes []*Sandbox `json:",omitempty"` // sandboxes defined in GoFiles and CgoFiles
+
 	// Error information
 	// Incomplete is above, packed into the other bools
 	Error      *PackageError   `json:",omitempty"` // error loading this package (not dependencies)
@@ -214,6 +214,8 @@ func (b *Builder) buildActionID(a *Action) cache.ActionID {
 	}
 	fmt.Fprintf(h, "goos %s goarch %s\n", cfg.Goos, cfg.Goarch)
 	fmt.Fprintf(h, "import %q\n", p.ImportPath)
+	// @aghosn the package id ends up in the generated sandbox ids.
+	fmt.Fprintf(h, "pkgid %d\n", a.spkgId)
 	fmt.Fprintf(h, "omitdebug %v standard %v local %v prefix %q\n", p.Internal.OmitDebug, p.Standard, p.Internal.Local, p.Internal.LocalPrefix)
 	if cfg.BuildTrimpath {
 		fmt.Fprintln(h, "trimpath")
@@ -1110,6 +1112,11 @@ func (b *Builder) linkActionID(a *Action) cache.ActionID {
 	// Toolchain-dependent configuration, shared with b.linkSharedActionID.
 	b.printLinkerConfig(h, p)
 
+	// @aghosn the linker checks the sandboxes against the module's policy.
+	if policy := gosbPolicy(p); policy != "" {
+		fmt.Fprintf(h, "gosbpolicy %s\n", b.fileHash(policy))
+	}
+
 	// Input files.
 	for _, a1 := range a.Deps {
 		p1 := a1.Package
@@ -1120,6 +1127,8 @@ func (b *Builder) linkActionID(a *Action) cache.ActionID {
 					buildID = b.buildID(a1.built)
 				}
 				fmt.Fprintf(h, "packagefile %s=%s\n", p1.ImportPath, contentID(buildID))
+				// @aghosn the linker lays out the sandboxed packages by id.
+				fmt.Fprintf(h, "packageid %s=%d\n", p1.ImportPath, a1.spkgId)
 			}
 			// Because we put package main's full action ID into the binary's build ID,
 			// we must also put the full action ID into the binary's action ID hash.
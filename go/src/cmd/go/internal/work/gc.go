@@ -507,6 +507,9 @@ func (gcToolchain) ld(b *Builder, root *Action, out, importcfg, mainpkg string)
 	if cfg.BuildBuildmode == "plugin" {
 		ldflags = append(ldflags, "-pluginpath", pluginPath(root))
 	}
+	if policy := gosbPolicy(root.Package); policy != "" {
+		ldflags = append(ldflags, "-gosbpolicy="+policy)
+	}
 
 	// Store BuildID inside toolchain binaries as a unique identifier of the
 	// tool being run, for use by content-based staleness determination.
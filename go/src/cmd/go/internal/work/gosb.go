@@ -3,7 +3,15 @@ package work
 import (
 	"bytes"
 	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+
+	"cmd/go/internal/load"
+
+	"gosb/commons"
 )
 
 var (
@@ -21,21 +29,13 @@ func rejectAction(a *Action) bool {
 
 func registerDependencies(root *Action) {
 	var workq []*Action
-	var inWorkq = make(map[*Action]int)
+	var inWorkq = make(map[*Action]bool)
 	var out bytes.Buffer
-	ids := 1
 	add := func(a *Action) {
-		if _, ok := inWorkq[a]; ok {
+		if inWorkq[a] {
 			return
 		}
-		if !rejectAction(a) && a.Package.ImportPath == "runtime" {
-			inWorkq[a] = 0
-			a.spkgId = 0
-		} else {
-			inWorkq[a] = ids
-			a.spkgId = ids
-			ids++
-		}
+		inWorkq[a] = true
 		workq = append(workq, a)
 	}
 	add(root)
@@ -46,16 +46,27 @@ func registerDependencies(root *Action) {
 		}
 	}
 
+	// Ids only depend on the import paths, so that they are the same across
+	// builds of the same packages.
+	var paths []string
+	for _, a := range workq {
+		if !rejectAction(a) {
+			paths = append(paths, gosbPkgName(a))
+		}
+	}
+	ids := gosbPkgIds(paths)
+	for _, a := range workq {
+		if !rejectAction(a) {
+			a.spkgId = ids[gosbPkgName(a)]
+		}
+	}
+
 	for _, a := range workq {
 		id := a.spkgId
 		if rejectAction(a) {
 			continue
 		}
-		pname := a.Package.ImportPath
-		if pname == "command-line-arguments" {
-			pname = "main"
-		}
-		fmt.Fprintf(&out, "packagedecl %s=%d\n", pname, id)
+		fmt.Fprintf(&out, "packagedecl %s=%d\n", gosbPkgName(a), id)
 		dependencies := make([]*Action, 0)
 		for _, a1 := range a.Deps {
 			if rejectAction(a1) {
@@ -68,7 +79,7 @@ func registerDependencies(root *Action) {
 		}
 		fmt.Fprintf(&out, "packagedep %d=", id)
 		for j, a1 := range dependencies {
-			fmt.Fprintf(&out, "%d", inWorkq[a1])
+			fmt.Fprintf(&out, "%d", a1.spkgId)
 			if j == len(dependencies)-1 {
 				fmt.Fprintf(&out, "\n")
 			} else {
@@ -78,3 +89,58 @@ func registerDependencies(root *Action) {
 	}
 	allDeps = out.Bytes()
 }
+
+// gosbPkgName returns the name under which the linker knows the package
+// built by a.
+func gosbPkgName(a *Action) string {
+	if a.Package.ImportPath == "command-line-arguments" {
+		return "main"
+	}
+	return a.Package.ImportPath
+}
+
+// gosbMaxPkgId bounds the package ids. Negative ids and ids above the bound
+// are left to the linker and the gosb runtime.
+const gosbMaxPkgId = 1 << 30
+
+// gosbPkgIds derives the package ids from a hash of the import paths.
+// The runtime is always 0. Collisions are resolved by probing the next id,
+// in the sorted order of the paths, so that the same set of packages always
+// gets the same ids.
+func gosbPkgIds(paths []string) map[string]int {
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+	ids := make(map[string]int)
+	used := map[int]bool{0: true}
+	for _, p := range sorted {
+		if _, ok := ids[p]; ok {
+			continue
+		}
+		if p == "runtime" {
+			ids[p] = 0
+			continue
+		}
+		h := fnv.New32a()
+		h.Write([]byte(p))
+		id := int(h.Sum32()%(gosbMaxPkgId-1)) + 1
+		for used[id] {
+			id = id%(gosbMaxPkgId-1) + 1
+		}
+		used[id] = true
+		ids[p] = id
+	}
+	return ids
+}
+
+// gosbPolicy returns the policy file that bounds the privileges of the
+// sandboxes of the main package p, at the root of its module, if any.
+func gosbPolicy(p *load.Package) string {
+	if p.Module == nil || p.Module.Dir == "" {
+		return ""
+	}
+	file := filepath.Join(p.Module.Dir, commons.PolicyFile)
+	if _, err := os.Stat(file); err != nil {
+		return ""
+	}
+	return file
+}
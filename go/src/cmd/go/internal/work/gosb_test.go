@@ -0,0 +1,27 @@
+package work
+
+import "testing"
+
+func TestGosbPkgIds(t *testing.T) {
+	ids := gosbPkgIds([]string{"runtime", "main", "fmt", "os", "fmt"})
+	if ids["runtime"] != 0 {
+		t.Errorf("runtime has id %d, want 0", ids["runtime"])
+	}
+	seen := make(map[int]string)
+	for p, id := range ids {
+		if prev, ok := seen[id]; ok {
+			t.Errorf("%s and %s share id %d", prev, p, id)
+		}
+		seen[id] = p
+		if id < 0 || id >= gosbMaxPkgId {
+			t.Errorf("%s has id %d out of range", p, id)
+		}
+	}
+	// The ids do not depend on the order in which packages are found.
+	again := gosbPkgIds([]string{"os", "fmt", "main", "runtime"})
+	for p, id := range ids {
+		if again[p] != id {
+			t.Errorf("%s has id %d, then %d", p, id, again[p])
+		}
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"cmd/go/internal/fmtcmd"
 	"cmd/go/internal/generate"
 	"cmd/go/internal/get"
+	"cmd/go/internal/gosbcmd"
 	"cmd/go/internal/help"
 	"cmd/go/internal/list"
 	"cmd/go/internal/modcmd"
@@ -54,6 +55,7 @@ func init() {
 		list.CmdList,
 		modcmd.CmdMod,
 		run.CmdRun,
+		gosbcmd.CmdSandbox,
 		test.CmdTest,
 		tool.CmdTool,
 		version.CmdVersion,
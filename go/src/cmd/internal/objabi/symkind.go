@@ -67,6 +67,9 @@ const (
 	// TODO(austin): Remove this and all uses once the compiler
 	// generates real ABI wrappers rather than symbol aliases.
 	SABIALIAS
+	// @aghosn, sandbox information recorded by the compiler for the linker.
+	// It never appears in the output.
+	SGOSB
 	// Update cmd/link/internal/sym/AbiSymKindToSymKind for new SymKind values.
 
 )
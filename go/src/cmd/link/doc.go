@@ -77,6 +77,16 @@ Flags:
 		Ignore version mismatch in the linked archives.
 	-g
 		Disable Go package data checks.
+	-gosbmap file
+		Write the sandbox layout of the binary to file: the sections of
+		each bloated package, including the trusted non-bloat package, and
+		for each sandbox its view, packages, syscalls and fake package.
+		With -linkmode=external, addresses are those of the Go object
+		passed to the host linker.
+	-gosbguard
+		Insert an unmapped guard page between the text, data and bss
+		sections of neighbouring bloated packages. Accesses to a guard
+		page are reported as sandbox violations.
 	-importcfg file
 		Read import configuration from file.
 		In the file, set packagefile, packageshlib to specify import resolution.
@@ -114,6 +124,19 @@ Flags:
 		Link with race detection libraries.
 	-s
 		Omit the symbol table and debug information.
+	-sandboxdeps
+		Report the packages reachable from each sandbox, along with the
+		edges of the call graph that reached them.
+	-sandboxreflect
+		Once a sandbox can call methods via reflection, consider the
+		exported methods of every type in the binary reachable from it.
+		Without this flag, the memory views are not widened for them.
+	-sandboxstrict list
+		Reject imports of unsafe, //go:linkname directives, assembly
+		and cgo in the packages mapped in the listed sandboxes, given as
+		a comma-separated list of sandbox ids or function names.
+		Use "all" to apply the check to every sandbox in the binary.
+		The standard library is trusted and never rejected.
 	-shared
 		Generated shared object (implies -linkmode external; experimental).
 	-tmpdir dir
@@ -1088,6 +1088,10 @@ func (p *GCProg) AddSym(s *sym.Symbol) {
 			// the address function.
 			return
 		}
+		// @aghosn gosb guard pages are never mapped, they hold no pointers.
+		if strings.HasPrefix(s.Name, gosbGuardPrefix) {
+			return
+		}
 		Errorf(s, "missing Go type information for global symbol: size %d", s.Size)
 		return
 	}
@@ -1305,7 +1309,7 @@ func (ctxt *Link) dodata() {
 	wg.Wait()
 
 	// We reorder symbols
-	bloatData(data)
+	ctxt.bloatData(&data, &dataMaxAlign)
 
 	if ctxt.HeadType == objabi.Haix && ctxt.LinkMode == LinkExternal {
 		// These symbols must have the same alignment as their section.
@@ -2021,7 +2025,7 @@ func (ctxt *Link) buildinfo() {
 // assign addresses to text
 func (ctxt *Link) textaddress() {
 	// @aghosn ensure that sandboxes are at the end.
-	bloatText(&ctxt.Textp)
+	ctxt.bloatText(&ctxt.Textp)
 
 	addsection(ctxt.Arch, &Segtext, ".text", 05)
 
@@ -2031,6 +2035,11 @@ func (ctxt *Link) textaddress() {
 	sect := Segtext.Sections[0]
 
 	sect.Align = int32(Funcalign)
+	if HasSandboxes() {
+		// Bloated packages are page aligned, the section must be too for
+		// the host linker to preserve the segregation.
+		sect.Align = 0x1000
+	}
 
 	text := ctxt.Syms.Lookup("runtime.text", 0)
 	text.Sect = sect
@@ -7,6 +7,7 @@ package ld
 import (
 	"cmd/internal/objabi"
 	"cmd/internal/sys"
+	"cmd/link/internal/objfile"
 	"cmd/link/internal/sym"
 	"fmt"
 	"strings"
@@ -252,6 +253,11 @@ func (d *deadcodepass) init() {
 		}
 	}
 
+	// @aghosn gosb looks up the functions of all the sandboxes at startup.
+	for _, sb := range objfile.Sandboxes {
+		names = append(names, sb.Func)
+	}
+
 	for _, name := range names {
 		// Mark symbol as an data/ABI0 symbol.
 		d.mark(d.ctxt.Syms.ROLookup(name, 0), nil)
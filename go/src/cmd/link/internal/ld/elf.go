@@ -1933,6 +1933,9 @@ func Asmbelf(ctxt *Link, symo int64) {
 		elfphrelro(&Segrelrodata)
 	}
 	elfphload(&Segdata)
+	if Segbloat.Filelen > 0 {
+		elfphload(&Segbloat)
+	}
 
 	/* Dynamic linking sections */
 	if !*FlagD {
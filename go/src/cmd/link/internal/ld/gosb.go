@@ -26,22 +26,36 @@ var (
 	EnableHiddenSymbols = false
 )
 
-func bloatText(text *[]*sym.Symbol) {
-	*text = gosb_reorderSymbols(int(sym.STEXT), *text)
+func (ctxt *Link) bloatText(text *[]*sym.Symbol) {
+	*text = ctxt.gosb_reorderSymbols(int(sym.STEXT), *text)
 }
 
-func bloatData(data [sym.SXREF][]*sym.Symbol) {
+// bloatData reorders data symbols and raises maxAlign to the page alignment
+// of the bloated packages, so that the sections are page aligned too.
+func (ctxt *Link) bloatData(data *[sym.SXREF][]*sym.Symbol, maxAlign *[sym.SXREF]int32) {
 	for i := range data {
-		// Required because data is an array... thank you go, you suck.
-		up := gosb_reorderSymbols(i, data[i])
-		copy(data[i], up)
+		up := ctxt.gosb_reorderSymbols(i, data[i])
+		data[i] = up
+		for _, s := range up {
+			if s.Align > maxAlign[i] {
+				maxAlign[i] = s.Align
+			}
+		}
 	}
 }
 
 // ignoreSection ignores itablink because all links are by default inside runtime
 // with our fix.
+// The ELF notes, the build info and the TLS template are left out of the
+// metadata as well: Go code never reads them, no package owns them, and the
+// host linker places them in sections that no gosb base covers. The address
+// of the TLS template is an offset from the thread pointer anyway.
 func ignoreSection(sel int) bool {
-	return sel == int(sym.SITABLINK)
+	switch sym.SymKind(sel) {
+	case sym.SITABLINK, sym.SELFROSECT, sym.SBUILDINFO, sym.STLSBSS:
+		return true
+	}
+	return false
 }
 
 func (ctxt *Link) dumpGosbSections(order []*sym.Segment, fsize *uint64) {
@@ -61,7 +75,7 @@ func (ctxt *Link) dumpGosbSections(order []*sym.Segment, fsize *uint64) {
 		//Addstring(shstrtab, sn)
 		addsection(ctxt.Arch, &Segbloat, sn, 04)
 		s := ctxt.Syms.Lookup(sn, 0)
-		s.P = gosb_generateContent(sn) //genbloat(sn)
+		s.P = ctxt.gosb_generateContent(sn) //genbloat(sn)
 		s.Size = int64(len(s.P))
 		s.Type = sym.SBLOAT
 		s.Sect = Segbloat.Sections[i]
@@ -79,6 +93,10 @@ func (ctxt *Link) dumpGosbSections(order []*sym.Segment, fsize *uint64) {
 	for _, s := range bloatsyms {
 		sect := s.Sect
 		s.Value += int64(sect.Vaddr)
+		if m := ctxt.Syms.ROLookup(sectMarkers[s.Name], 0); m != nil {
+			m.Sect = sect
+			m.Value = s.Value
+		}
 	}
 
 	// Give the fileoffset, it is important to do it before elfshbits.
@@ -3,7 +3,7 @@ package ld
 import (
 	"cmd/link/internal/objfile"
 	"cmd/link/internal/sym"
-	"encoding/json"
+	"fmt"
 	lb "gosb/commons"
 	"log"
 	"sort"
@@ -13,13 +13,36 @@ import (
 var (
 	nonbloat *lb.Package
 	//	stmps     *lb.Package
-	Bloats    map[string]*lb.Package
-	toSym     map[*lb.Section][]*sym.Symbol
-	lookup    map[int]string
-	domains   []*lb.SandboxDomain
-	sectNames = []string{".fake", ".bloated", ".sandboxes"}
+	Bloats     map[string]*lb.Package
+	toSym      map[*lb.Section][]*sym.Symbol
+	lookup     map[int]string
+	domains    []*lb.SandboxDomain
+	guards     []gosbGuard
+	sandOwned  map[string][]*sym.Symbol
+	sandOwners map[*sym.Symbol]string
+	sectNames  = []string{".fake", ".bloated", ".sandboxes", ".gosbsyms"}
+
+	// sectMarkers name the start of the gosb sections so that they can be
+	// referenced from runtime.gosbsections by the host linker too.
+	sectMarkers = map[string]string{
+		".bloated":   "runtime.gosbbloated",
+		".sandboxes": "runtime.gosbsandboxes",
+		".gosbsyms":  "runtime.gosbsyms",
+	}
+
+	// gosbBases are the symbols marking the start of the Go sections.
+	// Addresses in the gosb metadata are encoded relative to them, since the
+	// host linker moves the sections independently.
+	gosbBases = lb.SectionBases
 )
 
+// gosbGuard is a guard page inserted by -gosbguard between the symbols of
+// two neighbouring packages.
+type gosbGuard struct {
+	s             *sym.Symbol
+	before, after string
+}
+
 // computeBloats initializes global state and computes all dependencies for each
 // package that requires to be bloated.
 func (ctxt *Link) computeBloats() {
@@ -53,12 +76,14 @@ func (ctxt *Link) computeBloats() {
 	}
 
 	// Get the transitive dependencies for each package
+	ctxt.gosb_expandViews()
 	ctxt.registerExtraPackages()
+	ctxt.gosb_callGraphDeps()
 	for k := range objfile.SegregatedPkgs {
 		ctxt.gosb_walkTransDeps(k, create, check)
 	}
 	// Add an entry for non-bloated packages, and shared stmps
-	nonbloat = &lb.Package{lb.TrustedPkgName, -1, make([]lb.Section, sym.SABIALIAS), nil}
+	nonbloat = &lb.Package{Name: lb.TrustedPkgName, Id: -1, Sects: make([]lb.Section, sym.SABIALIAS)}
 
 	for i := range nonbloat.Sects {
 		nonbloat.Sects[i].Prot = symKindtoProt(sym.SymKind(i))
@@ -66,6 +91,73 @@ func (ctxt *Link) computeBloats() {
 	// For all the sandboxes, we get the transitive dependencies & generate
 	// the sandboxes informations.
 	ctxt.gosb_generateDomains()
+	ctxt.gosb_checkStrict()
+}
+
+// gosb_expandViews replaces the package patterns in the memory views of the
+// sandboxes by the packages of the binary that they match, and registers these
+// packages to be bloated.
+// Patterns only expand to the packages that the package of the sandbox can
+// import, and never to the packages of gosb_backendPkgs. The standard library
+// declares no sandbox, so none of them can import internal/....
+func (ctxt *Link) gosb_expandViews() {
+	var pkgs []string
+	for i := range objfile.Sandboxes {
+		sb := &objfile.Sandboxes[i]
+		view := make(map[string]uint8)
+		patterns := false
+		for _, e := range sb.Extras {
+			view[e.Name] = e.Perm
+			patterns = patterns || lb.IsPackagePattern(e.Name)
+		}
+		if !patterns {
+			continue
+		}
+		if pkgs == nil {
+			skip := ctxt.gosb_backendPkgs()
+			for p := range ctxt.PackageDecl {
+				if !skip[p] {
+					pkgs = append(pkgs, p)
+				}
+			}
+			sort.Strings(pkgs)
+		}
+		var importable []string
+		for _, p := range pkgs {
+			if lb.CanImport(sb.Pkg, p, false) {
+				importable = append(importable, p)
+			}
+		}
+		view = lb.ExpandMemoryView(view, importable)
+		sb.Extras = make([]lb.Entry, 0, len(view))
+		for name, perm := range view {
+			sb.Extras = append(sb.Extras, lb.Entry{Name: name, Perm: perm})
+			objfile.SegregatedPkgs[name] = true
+		}
+		sort.Slice(sb.Extras, func(i, j int) bool {
+			return sb.Extras[i].Name < sb.Extras[j].Name
+		})
+	}
+}
+
+// gosb_backendPkgs returns the runtime and its dependencies, which are mapped
+// in every sandbox, along with gosb and its subpackages, which enforce the
+// sandboxes. No sandbox can change the rights of these packages.
+func (ctxt *Link) gosb_backendPkgs() map[string]bool {
+	res := make(map[string]bool)
+	ctxt.gosb_walkTransDeps("runtime", func(ctxt *Link, id int, deps []int) {}, func(s string) bool {
+		if res[s] {
+			return true
+		}
+		res[s] = true
+		return false
+	})
+	for p := range ctxt.PackageDecl {
+		if lb.IsBackendPackage(p) {
+			res[p] = true
+		}
+	}
+	return res
 }
 
 // addExtraPackages registers packages that are not sandbox dependencies
@@ -77,8 +169,11 @@ func (ctxt *Link) registerExtraPackages() {
 		objfile.SegregatedPkgs["runtime/cgo"] = true
 		objfile.SegregatedPkgs["runtime/cgo2"] = true
 		if _, ok := ctxt.PackageDecl["runtime/cgo"]; ok {
-			Bloats["runtime/cgo2"] = &lb.Package{"runtime/cgo2", -3, make([]lb.Section, sym.SABIALIAS), nil}
+			Bloats["runtime/cgo2"] = &lb.Package{Name: "runtime/cgo2", Id: -3, Sects: make([]lb.Section, sym.SABIALIAS)}
 		}
+		// Read-only data shared between packages, see gosb_attributeRodata.
+		objfile.SegregatedPkgs[lb.SharedRodataPkgName] = true
+		Bloats[lb.SharedRodataPkgName] = &lb.Package{Name: lb.SharedRodataPkgName, Id: -4, Sects: make([]lb.Section, sym.SABIALIAS)}
 	}
 }
 
@@ -89,10 +184,14 @@ func (ctxt *Link) extraDependencies() []string {
 		res = append(res, "runtime/cgo")
 		res = append(res, "runtime/cgo2")
 	}
+	if _, ok := Bloats[lb.SharedRodataPkgName]; ok {
+		res = append(res, lb.SharedRodataPkgName)
+	}
 	return res
 }
 
 func (ctxt *Link) gosb_generateDomains() {
+	policy := gosb_loadPolicy()
 	for _, v := range objfile.Sandboxes {
 		sb := &lb.SandboxDomain{}
 		sb.Id = v.Id
@@ -142,9 +241,16 @@ func (ctxt *Link) gosb_generateDomains() {
 		for _, pack := range visited {
 			sb.Pkgs = append(sb.Pkgs, pack.Name)
 		}
+		sort.Strings(sb.Pkgs)
 		sb.View = memView
+		if policy != nil {
+			gosb_checkPolicy(policy, sb, v.Pkg)
+		}
 		domains = append(domains, sb)
 	}
+	if nerrors > 0 {
+		errorexit()
+	}
 	// Create a fake sandbox for the nonbloated domain
 	nonbloatDomain := &lb.SandboxDomain{}
 	nonbloatDomain.Id = "-1"
@@ -190,7 +296,9 @@ func (ctxt *Link) gosb_walkTransDeps(top string, f func(ctxt *Link, id int, deps
 // part as well.
 // Sandboxes symbols are put at the very end of things.
 // We also have to handle the sandbox information.
-func gosb_reorderSymbols(sel int, syms []*sym.Symbol) []*sym.Symbol {
+// With -gosbguard, a guard page separates each bloated package from its
+// neighbours.
+func (ctxt *Link) gosb_reorderSymbols(sel int, syms []*sym.Symbol) []*sym.Symbol {
 	// Fast exit if we do not have sandboxes or if it is a section we don't care about
 	if len(objfile.Sandboxes) == 0 || ignoreSection(sel) || len(syms) == 0 {
 		return syms
@@ -202,6 +310,10 @@ func gosb_reorderSymbols(sel int, syms []*sym.Symbol) []*sym.Symbol {
 	bloated := make(map[string][]*sym.Symbol)
 	sandSyms := make([]*sym.Symbol, 0)
 	specials := make([]*sym.Symbol, 0)
+	if sel == int(sym.STEXT) {
+		sandOwners = gosb_sandboxOwners(syms)
+		sandOwned = make(map[string][]*sym.Symbol)
+	}
 	for _, s := range syms {
 		// Safety check to avoid go.itab and go.runtime
 		if s.File == "go.runtime" || s.File == "go.itab" {
@@ -211,6 +323,9 @@ func gosb_reorderSymbols(sel int, syms []*sym.Symbol) []*sym.Symbol {
 		if _, ok := objfile.SBMap[s.Name]; ok {
 			sandSyms = append(sandSyms, s)
 			s.Align = 0x1000
+		} else if owner, ok := sandOwners[s]; ok && sel == int(sym.STEXT) {
+			// Code owned by a sandbox goes in its fake package.
+			sandOwned[owner] = append(sandOwned[owner], s)
 		} else if isSandboxStkObj(s.Name, s) || s.Name == "main.main.stkobj" {
 			// Isolate stack object for sandbox code
 			sandSyms = append(sandSyms, s)
@@ -253,27 +368,150 @@ func gosb_reorderSymbols(sel int, syms []*sym.Symbol) []*sym.Symbol {
 	// We register the regsyms as well for the nonbloated.
 	toSym[&nonbloat.Sects[sel]] = regSyms
 	// Align symbols
+	prev := nonbloat.Name
 	for _, s := range fmap {
+		if len(regSyms) > 0 {
+			regSyms = ctxt.gosb_addGuard(sel, regSyms, prev, s[0].File)
+		}
 		s[0].Align = 0x1000
 		regSyms = append(regSyms, s...)
+		prev = s[0].File
+	}
+	next := specials
+	if len(next) == 0 {
+		next = sandSyms
+	}
+	if len(fmap) > 0 && len(next) > 0 {
+		regSyms = ctxt.gosb_addGuard(sel, regSyms, prev, gosb_guardOwner(next[0]))
 	}
 	regSyms = append(regSyms, specials...)
-	regSyms = append(regSyms, sandSyms...)
+	for _, s := range sandSyms {
+		regSyms = append(regSyms, s)
+		regSyms = append(regSyms, sandOwned[s.Name]...)
+	}
 	return regSyms
 }
 
-func gosb_generateContent(sect string) []byte {
+// gosb_sandboxOwners maps the text symbols that belong to a single sandbox to
+// that sandbox's function. These are the closures nested in the sandbox
+// literal and the compiler-generated wrappers, e.g., method values, that
+// are only referenced from within the sandbox.
+func gosb_sandboxOwners(text []*sym.Symbol) map[*sym.Symbol]string {
+	owners := make(map[*sym.Symbol]string)
+	for _, s := range text {
+		if owner := gosb_lexicalOwner(s.Name); owner != "" {
+			owners[s] = owner
+		}
+	}
+	// A wrapper belongs to a sandbox if all its users belong to it. Method
+	// values reference their wrapper through its funcval symbol, X-fm·f.
+	users := make(map[string]string)
+	for _, s := range text {
+		user := owners[s]
+		if _, ok := objfile.SBMap[s.Name]; ok {
+			user = s.Name
+		}
+		for _, r := range s.R {
+			if r.Sym == nil {
+				continue
+			}
+			name := strings.TrimSuffix(r.Sym.Name, "·f")
+			if !strings.HasSuffix(name, "-fm") {
+				continue
+			}
+			if prev, ok := users[name]; ok && prev != user {
+				users[name] = ""
+				continue
+			}
+			users[name] = user
+		}
+	}
+	for _, s := range text {
+		if user := users[s.Name]; user != "" {
+			owners[s] = user
+		}
+	}
+	return owners
+}
+
+// gosb_lexicalOwner returns the sandbox function that lexically encloses the
+// closure name, or "" if there is none. The closures of a function F are
+// named F.func1, F.func2, etc., nested closures of a closure f are named f.1,
+// f.1.2, etc. Closures belong to the innermost enclosing sandbox.
+func gosb_lexicalOwner(name string) string {
+	for i := len(name) - 1; i > 0; i-- {
+		if name[i] != '.' {
+			continue
+		}
+		suffix := strings.TrimPrefix(name[i+1:], "func")
+		if suffix == "" || suffix[0] < '0' || suffix[0] > '9' {
+			continue
+		}
+		if _, ok := objfile.SBMap[name[:i]]; ok {
+			return name[:i]
+		}
+	}
+	return ""
+}
+
+// gosb_sandboxSize returns the size of the code of the sandbox function f,
+// including the symbols it owns, which the linker lays out right after it.
+func gosb_sandboxSize(f *sym.Symbol) int64 {
+	owned := sandOwned[f.Name]
+	if len(owned) == 0 {
+		return f.Size
+	}
+	last := owned[len(owned)-1]
+	return last.Value + last.Size - f.Value
+}
+
+// gosbGuardPrefix starts the names of the guard symbols.
+const gosbGuardPrefix = "go.gosb.guard."
+
+// gosb_addGuard appends to syms a page sized symbol of kind sel that
+// separates the packages before and after, if -gosbguard is set.
+// The symbol belongs to no package and is therefore mapped in no view.
+// It holds no pointers, even in the data and bss sections, see GCProg.AddSym.
+func (ctxt *Link) gosb_addGuard(sel int, syms []*sym.Symbol, before, after string) []*sym.Symbol {
+	if !*flagGosbGuard {
+		return syms
+	}
+	s := ctxt.Syms.Lookup(fmt.Sprintf("%s%d", gosbGuardPrefix, len(guards)), 0)
+	s.Type = sym.SymKind(sel)
+	s.Size = 0x1000
+	s.Align = 0x1000
+	s.Attr |= sym.AttrReachable
+	guards = append(guards, gosbGuard{s, before, after})
+	return append(syms, s)
+}
+
+// gosb_guardOwner returns the name of the package that owns s in the gosb
+// metadata, for symbols that follow the bloated packages.
+func gosb_guardOwner(s *sym.Symbol) string {
+	name := strings.TrimSuffix(s.Name, ".stkobj")
+	if _, ok := objfile.SBMap[name]; ok {
+		// Sandbox functions are in their own fake package.
+		return name
+	}
+	if s.Name == "runtime.pclntab" {
+		return "runtime"
+	}
+	return nonbloat.Name
+}
+
+func (ctxt *Link) gosb_generateContent(sect string) []byte {
 	switch sect {
 	case ".fake":
 		return []byte("fake")
 	case ".bloated":
-		return gosb_dumpPackages()
+		return ctxt.gosb_dumpPackages()
 	case ".sandboxes":
 		return gosb_dumpSandboxes()
+	case ".gosbsyms":
+		return ctxt.gosb_dumpSymbols()
 	default:
 		panic("Unknown value for gosb_generateContent")
 	}
-	return nil
 }
 
 func isSandboxStkObj(name string, s *sym.Symbol) bool {
@@ -288,9 +526,9 @@ func isSandboxStkObj(name string, s *sym.Symbol) bool {
 	return ok
 }
 
-// gosb_dumpPackages returns the marshalled json bytes that correspond
+// gosb_dumpPackages returns the encoded bytes that correspond
 // to packages. we go through each register section to set the addresses.
-func gosb_dumpPackages() []byte {
+func (ctxt *Link) gosb_dumpPackages() []byte {
 	// Register the final addresses for the sections.
 	// This actually also handles the non-bloat part.
 	for k, v := range toSym {
@@ -310,6 +548,7 @@ func gosb_dumpPackages() []byte {
 		// We just verify that symbols are increasing and all belong to the same
 		// package.
 		gosb_verifySymbols(v, ok)
+		ctxt.gosb_checkBase(first)
 		k.Addr = uint64(first.Value)
 		k.Size = uint64(last.Value-first.Value) + uint64(last.Size)
 		k.Prot = lb.W_VAL | lb.R_VAL
@@ -322,13 +561,23 @@ func gosb_dumpPackages() []byte {
 	for _, b := range Bloats {
 		res = append(res, b)
 	}
+	// Sort for reproducible metadata.
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
 	// Add the non-bloated part
 	res = append(res, nonbloat)
-	b, err := json.Marshal(res)
-	if err != nil {
-		log.Fatalf("Unable to marshal %v\n", err.Error())
+	return lb.EncodePackages(res, gosb_guards(), ctxt.gosb_bases())
+}
+
+// gosb_guards returns the guard pages with their final addresses.
+func gosb_guards() []lb.Guard {
+	res := make([]lb.Guard, 0, len(guards))
+	for _, g := range guards {
+		sect := lb.Section{Addr: uint64(Symaddr(g.s)), Size: uint64(g.s.Size)}
+		res = append(res, lb.Guard{Section: sect, Before: g.before, After: g.after})
 	}
-	return b
+	return res
 }
 
 func gosb_verifySymbols(syms []*sym.Symbol, aligned bool) {
@@ -352,11 +601,97 @@ func gosb_verifySymbols(syms []*sym.Symbol, aligned bool) {
 }
 
 func gosb_dumpSandboxes() []byte {
-	res, err := json.Marshal(domains)
-	if err != nil {
-		log.Fatalf("Error mashalling sandboxes %v\n", err.Error())
+	return lb.EncodeDomains(domains)
+}
+
+// gosb_sandboxFunc returns the symbol of the sandbox function name.
+// Go functions are ABIInternal, assembly ones ABI0.
+func (ctxt *Link) gosb_sandboxFunc(name string) *sym.Symbol {
+	f := ctxt.Syms.ROLookup(name, sym.SymVerABIInternal)
+	if f == nil {
+		f = ctxt.Syms.ROLookup(name, 0)
+	}
+	if f == nil {
+		log.Fatalf("Missing symbol for sandbox %v\n", name)
+	}
+	return f
+}
+
+// gosb_dumpSymbols returns the symbols gosb looks up at startup: the sandbox
+// functions, their stack objects and runtime.pclntab.
+func (ctxt *Link) gosb_dumpSymbols() []byte {
+	var syms []*sym.Symbol
+	names := []string{"main.main.stkobj", "runtime.pclntab"}
+	for _, d := range domains {
+		if d.Id == "-1" {
+			continue
+		}
+		f := ctxt.gosb_sandboxFunc(d.Func)
+		if !f.Attr.Reachable() {
+			log.Fatalf("Unreachable symbol for sandbox %v\n", d.Func)
+		}
+		syms = append(syms, f)
+		names = append(names, d.Func+".stkobj")
+	}
+	for _, n := range names {
+		if s := ctxt.Syms.ROLookup(n, 0); s != nil && s.Attr.Reachable() {
+			syms = append(syms, s)
+		}
+	}
+	var res []lb.Symbol
+	for _, s := range syms {
+		ctxt.gosb_checkBase(s)
+		size := s.Size
+		if _, ok := objfile.SBMap[s.Name]; ok {
+			size = gosb_sandboxSize(s)
+		}
+		res = append(res, lb.Symbol{Name: s.Name, Addr: uint64(Symaddr(s)), Size: uint64(size)})
+	}
+	return lb.EncodeSymbols(res, ctxt.gosb_bases())
+}
+
+// gosb_bases returns the link-time addresses of gosbBases. At run time, gosb
+// reads their load addresses from runtime.gosbsections, which makes the
+// metadata valid for PIE and externally linked binaries.
+func (ctxt *Link) gosb_bases() []uint64 {
+	bases := make([]uint64, len(gosbBases))
+	for i, n := range gosbBases {
+		bases[i] = uint64(Symaddr(ctxt.Syms.Lookup(n, 0)))
+	}
+	return bases
+}
+
+// gosb_checkBase verifies that s is encoded relative to a base in its own
+// section when linking externally.
+func (ctxt *Link) gosb_checkBase(s *sym.Symbol) {
+	if ctxt.LinkMode != LinkExternal || s.Sect == nil {
+		return
+	}
+	i := lb.BaseOf(ctxt.gosb_bases(), uint64(Symaddr(s)))
+	if b := ctxt.Syms.Lookup(gosbBases[i], 0); b.Sect != s.Sect {
+		Errorf(s, "no gosb base in section %s, cannot link sandboxes externally", s.Sect.Name)
+	}
+}
+
+// gosb_defineSections fills runtime.gosbsections with the addresses of the
+// sections gosb reads at startup and of gosbBases. The sections are laid out
+// by dumpGosbSections, the relocations are resolved afterwards.
+func (ctxt *Link) gosb_defineSections() {
+	if !HasSandboxes() {
+		return
+	}
+	s := ctxt.Syms.Lookup("runtime.gosbsections", 0)
+	s.Type = sym.SNOPTRDATA
+	s.Attr |= sym.AttrReachable
+	s.Size = 0 // overwrite existing value
+	s.P = s.P[:0]
+	for _, sn := range sectNames[1:] {
+		ctxt.xdefine(sectMarkers[sn], sym.SBLOAT, 0)
+		s.AddAddr(ctxt.Arch, ctxt.Syms.Lookup(sectMarkers[sn], 0))
+	}
+	for _, n := range gosbBases {
+		s.AddAddr(ctxt.Arch, ctxt.Syms.Lookup(n, 0))
 	}
-	return res
 }
 
 // Translate a section's idx into protection
@@ -441,6 +776,143 @@ func (ctxt *Link) fixingStupidSymbols() {
 	}
 }
 
+// gosb_attributeRodata attributes type descriptors, itabs and string literals
+// to their package instead of pooling them in the runtime, so that sandboxes
+// only read the constants of the packages in their view.
+// A symbol belongs to the only package that references it, directly or
+// through other such symbols. Named types are also used by the package that
+// declares them. Itabs, unnamed types and symbols that are shared between
+// packages, or used by sandbox code, go into the shared read-only pool, which
+// all sandboxes can read.
+func (ctxt *Link) gosb_attributeRodata() {
+	if !HasSandboxes() {
+		return
+	}
+	if _, ok := Bloats[lb.SharedRodataPkgName]; !ok {
+		return
+	}
+	// Packages fixed by the symbol name.
+	fixed := make(map[*sym.Symbol]string)
+	// Packages that use the other symbols, computed below.
+	users := make(map[*sym.Symbol]string)
+	join := func(s *sym.Symbol, user string) bool {
+		prev := users[s]
+		switch {
+		case user == "" || prev == user || prev == lb.SharedRodataPkgName:
+			return false
+		case prev == "":
+			users[s] = user
+		default:
+			users[s] = lb.SharedRodataPkgName
+		}
+		return true
+	}
+	type edge struct{ from, to *sym.Symbol }
+	var edges []edge
+	for _, s := range ctxt.Syms.Allsym {
+		if !s.Attr.Reachable() || !gosb_isPooledRodata(s) {
+			continue
+		}
+		name := s.Name
+		if strings.HasPrefix(name, "go.itab.") {
+			// runtime.itabTable.find reads the itabs on its probe path,
+			// whichever sandbox does the lookup.
+			fixed[s] = lb.SharedRodataPkgName
+		} else if strings.HasPrefix(name, "type.") && !strings.HasPrefix(name, "type..") {
+			if p := ctxt.gosb_declaringPkg(strings.TrimPrefix(name, "type.")); p != "" {
+				join(s, p)
+			} else {
+				fixed[s] = lb.SharedRodataPkgName
+			}
+		}
+	}
+	for _, s := range ctxt.Syms.Allsym {
+		if !s.Attr.Reachable() || !gosb_isRodataUser(s) {
+			continue
+		}
+		for _, r := range s.R {
+			if r.Sym == nil || !gosb_isPooledRodata(r.Sym) {
+				continue
+			}
+			if _, ok := fixed[r.Sym]; ok {
+				continue
+			}
+			if gosb_isPooledRodata(s) {
+				edges = append(edges, edge{s, r.Sym})
+				continue
+			}
+			user := s.File
+			if _, ok := objfile.SBMap[s.Name]; ok {
+				user = lb.SharedRodataPkgName
+			} else if _, ok := sandOwners[s]; ok {
+				user = lb.SharedRodataPkgName
+			}
+			join(r.Sym, user)
+		}
+	}
+	// Propagate the users through the pooled symbols.
+	for changed := true; changed; {
+		changed = false
+		for _, e := range edges {
+			user, ok := fixed[e.from]
+			if !ok {
+				user = users[e.from]
+			}
+			changed = join(e.to, user) || changed
+		}
+	}
+	for s, p := range fixed {
+		s.File = p
+	}
+	for s, p := range users {
+		s.File = p
+	}
+}
+
+// gosb_isPooledRodata returns whether s is one of the read-only symbols that
+// the linker pools by default: type descriptors, itabs and string literals.
+func gosb_isPooledRodata(s *sym.Symbol) bool {
+	if s.Type == sym.STEXT || lb.SymToFix[s.Name] {
+		return false
+	}
+	return strings.HasPrefix(s.Name, "type.") ||
+		strings.HasPrefix(s.Name, "go.itab.") ||
+		strings.HasPrefix(s.Name, "go.string.")
+}
+
+// gosb_isRodataUser returns whether the references of s denote a use by its
+// package, rather than tables that only the runtime reads.
+func gosb_isRodataUser(s *sym.Symbol) bool {
+	switch {
+	case s.Type == sym.STEXT:
+		return true
+	case s.Type >= sym.STYPE && s.Type <= sym.SRODATARELRO && s.Type != sym.SFUNCTAB:
+		return !lb.SymToFix[s.Name]
+	case s.Type >= sym.SNOPTRDATA && s.Type <= sym.SNOPTRBSS:
+		return true
+	}
+	return false
+}
+
+// gosb_declaringPkg returns the package that declares the named type t, or ""
+// if t is not a named type of a package in the binary.
+func (ctxt *Link) gosb_declaringPkg(t string) string {
+	t = strings.TrimLeft(t, "*")
+	if strings.ContainsAny(t, "()[]{} ,;*") {
+		return ""
+	}
+	// The last element of the path may contain dots, e.g., gopkg.in/yaml.v2.
+	for i := strings.LastIndex(t, "/") + 1; i < len(t); i++ {
+		if t[i] != '.' {
+			continue
+		}
+		if _, ok := ctxt.PackageDecl[t[:i]]; ok {
+			return t[:i]
+		}
+	}
+	return ""
+}
+
 func (ctxt *Link) StupidText() {
 	// Fixing cgo functions
 	for _, s := range ctxt.Textp {
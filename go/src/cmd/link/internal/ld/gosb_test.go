@@ -0,0 +1,912 @@
+package ld
+
+import (
+	"context"
+	"debug/elf"
+	"fmt"
+	"internal/testenv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sandboxProg = `
+package main
+
+import (
+	"fmt"
+	"gosb"
+	"gosb/backend"
+	"strings"
+)
+
+type T struct{}
+
+func (T) String() string { return strings.ToUpper("t") }
+
+func init() {
+	gosb.Initialize(backend.SIM_BACKEND)
+}
+
+func main() {
+	f := sandbox ["fmt:R", ""] () {
+		fmt.Println(T{})
+	}
+	f()
+}
+
+// unused is dead code, its sandbox is kept nonetheless.
+func unused() {
+	sandbox ["", ""] () {}()
+}
+`
+
+// gosbBuild builds the program src with the given linker flags and returns
+// the path of the executable along with the output of the build.
+func gosbBuild(t *testing.T, dir, src, ldflags string) (string, string) {
+	return gosbBuildPkgs(t, dir, map[string]string{"main": src}, ldflags)
+}
+
+// gosbBuildPkgs builds the package main of a GOPATH in dir, made of the
+// given packages with a single source file each. The extra arguments are
+// passed to go build.
+func gosbBuildPkgs(t *testing.T, dir string, pkgs map[string]string, ldflags string, args ...string) (string, string) {
+	exe, out, err := gosbTryBuildPkgs(t, dir, pkgs, ldflags, args...)
+	if err != nil {
+		t.Fatalf("build: %v\n%s", err, out)
+	}
+	return exe, out
+}
+
+// gosbTryBuildPkgs is like gosbBuildPkgs, but returns the error of the build.
+func gosbTryBuildPkgs(t *testing.T, dir string, pkgs map[string]string, ldflags string, args ...string) (string, string, error) {
+	for path, src := range pkgs {
+		pdir := filepath.Join(dir, "src", path)
+		if err := os.MkdirAll(pdir, 0777); err != nil {
+			t.Fatal(err)
+		}
+		file := filepath.Join(pdir, filepath.Base(path)+".go")
+		if err := ioutil.WriteFile(file, []byte(src), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exe := filepath.Join(dir, "main.exe")
+	// A broken call graph used to spin forever, fail early instead.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	args = append([]string{"build", "-ldflags=" + ldflags, "-o", exe}, args...)
+	cmd := exec.CommandContext(ctx, testenv.GoToolPath(t), append(args, "main")...)
+	cmd.Env = append(os.Environ(), "GOPATH="+dir, "GO111MODULE=off")
+	out, err := cmd.CombinedOutput()
+	return exe, string(out), err
+}
+
+func TestSandboxCallGraph(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestSandboxCallGraph")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	_, out := gosbBuild(t, dir, sandboxProg, "-sandboxdeps")
+	if !strings.Contains(out, "sandbox main.main.func1\n") {
+		t.Fatalf("missing sandbox in -sandboxdeps report:\n%s", out)
+	}
+	// T.String is only called by fmt, through the fmt.Stringer interface.
+	if !strings.Contains(out, "\tstrings\n") {
+		t.Errorf("strings is not reachable from the sandbox:\n%s", out)
+	}
+	// fmt uses reflection, which must not widen the view without
+	// -sandboxreflect, and gosb is never part of it.
+	for _, pkg := range []string{"gosb", "gosb/mpk", "gosb/globals", "gosb/vtx/usermem"} {
+		if strings.Contains(out, "\t"+pkg+"\n") {
+			t.Errorf("%s is reachable from the sandbox:\n%s", pkg, out)
+		}
+	}
+	// Only the methods of the types the sandbox reaches are followed, and
+	// every chain of edges starts at the sandbox.
+	for _, pkg := range []string{"bufio", "hash/crc32"} {
+		if strings.Contains(out, "\t"+pkg+"\n") {
+			t.Errorf("%s is reachable from the sandbox:\n%s", pkg, out)
+		}
+	}
+	if strings.Contains(out, "\t\t-> ") {
+		t.Errorf("edge from an unreached symbol:\n%s", out)
+	}
+}
+
+const reflectProg = `
+package main
+
+import (
+	"fmt"
+	"gosb"
+	"gosb/backend"
+	"reflect"
+)
+
+type T struct{}
+
+func (T) Name() string { return "t" }
+
+func init() {
+	gosb.Initialize(backend.SIM_BACKEND)
+}
+
+func main() {
+	sandbox ["", ""] () {
+		fmt.Println(reflect.ValueOf(T{}).MethodByName("Name").Call(nil)[0])
+	}()
+}
+`
+
+func TestSandboxReflectWarning(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestSandboxReflectWarning")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const warning = "warning: sandbox main.main.func1"
+	_, out := gosbBuild(t, dir, reflectProg, "")
+	if !strings.Contains(out, warning) {
+		t.Errorf("no warning for a sandbox that uses reflection:\n%s", out)
+	}
+	_, out = gosbBuild(t, dir, reflectProg, "-sandboxreflect")
+	if strings.Contains(out, warning) {
+		t.Errorf("warning with -sandboxreflect:\n%s", out)
+	}
+}
+
+func TestSandboxRun(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestSandboxRun")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// gosb looks up the functions of the sandboxes at startup.
+	layout := filepath.Join(dir, "layout")
+	exe, _ := gosbBuild(t, dir, sandboxProg, "-gosbmap="+layout)
+	out, err := exec.Command(exe).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	if string(out) != "T\n" {
+		t.Errorf("got %q, want %q", out, "T\n")
+	}
+	data, err := ioutil.ReadFile(layout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"main.main.func1", "main.unused.func1"} {
+		if !strings.Contains(string(data), " func="+f+"\n") {
+			t.Errorf("no sandbox for %s in the layout:\n%s", f, data)
+		}
+	}
+}
+
+func TestSandboxGuard(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestSandboxGuard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Guards lie between the data of packages that hold pointers.
+	exe, _ := gosbBuild(t, dir, sandboxProg, "-gosbguard")
+	out, err := exec.Command(exe).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	if string(out) != "T\n" {
+		t.Errorf("got %q, want %q", out, "T\n")
+	}
+}
+
+func TestSandboxExternal(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	testenv.MustHaveCGO(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestSandboxExternal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The host linker places the sections, the metadata must still match
+	// the bases gosb finds at startup.
+	exe, _ := gosbBuild(t, dir, sandboxProg, "-linkmode=external")
+	out, err := exec.Command(exe).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	if string(out) != "T\n" {
+		t.Errorf("got %q, want %q", out, "T\n")
+	}
+}
+
+// pieProg prints the address main is loaded at before running a sandbox
+// with the backend given by the format argument.
+const pieProg = `
+package main
+
+import (
+	"fmt"
+	"gosb"
+	"gosb/backend"
+	"strings"
+)
+
+func init() {
+	gosb.Initialize(backend.%s)
+}
+
+func main() {
+	fmt.Printf("%%p\n", main)
+	sandbox ["fmt:R", ""] () {
+		fmt.Println(strings.ToUpper("t"))
+	}()
+}
+`
+
+func TestSandboxPIE(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	testenv.MustHaveCGO(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestSandboxPIE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// With memory protection keys, gosb also tags the relocated sections.
+	backend, env := "SIM_BACKEND", "LITTER=SIM"
+	if cpuinfo, err := ioutil.ReadFile("/proc/cpuinfo"); err == nil && strings.Contains(string(cpuinfo), " pku") {
+		backend, env = "MPK_BACKEND", "LITTER=MPK"
+	}
+	exe, _ := gosbBuildPkgs(t, dir, map[string]string{"main": fmt.Sprintf(pieProg, backend)}, "", "-buildmode=pie")
+	f, err := elf.Open(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if f.Type != elf.ET_DYN {
+		t.Fatalf("got ELF type %v, want %v", f.Type, elf.ET_DYN)
+	}
+	syms, err := f.Symbols()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var link uint64
+	for _, s := range syms {
+		if s.Name == "main.main" {
+			link = s.Value
+		}
+	}
+
+	addrs := make(map[uint64]bool)
+	for i := 0; i < 2; i++ {
+		cmd := exec.Command(exe)
+		cmd.Env = append(os.Environ(), env)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%v\n%s", err, out)
+		}
+		var addr uint64
+		var res string
+		if _, err := fmt.Sscanf(string(out), "%v\n%s\n", &addr, &res); err != nil || res != "T" {
+			t.Fatalf("got %q, want the address of main followed by %q", out, "T\n")
+		}
+		if addr == link {
+			t.Errorf("main runs at its link address %#x", link)
+		}
+		addrs[addr] = true
+	}
+	aslr, err := ioutil.ReadFile("/proc/sys/kernel/randomize_va_space")
+	if err == nil && strings.TrimSpace(string(aslr)) != "0" && len(addrs) != 2 {
+		t.Errorf("both runs loaded main at the same address %v", addrs)
+	}
+}
+
+const guardProg = `
+package main
+
+import (
+	"fmt"
+	"gosb"
+	"gosb/backend"
+	"unsafe"
+	"x"
+)
+
+func init() {
+	gosb.Initialize(backend.SIM_BACKEND)
+}
+
+func main() {
+	sandbox ["x:R", ""] () {
+		// x.B starts the data of x, a guard page precedes it.
+		p := uintptr(unsafe.Pointer(&x.B[0]))&^0xfff - 0x1000
+		fmt.Println(*(*byte)(unsafe.Pointer(p)))
+	}()
+}
+`
+
+func TestSandboxGuardViolation(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestSandboxGuardViolation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pkgs := map[string]string{
+		"main": guardProg,
+		"x":    "package x\n\nvar B = [16]byte{1}\n",
+	}
+	exe, _ := gosbBuildPkgs(t, dir, pkgs, "-gosbguard")
+	out, err := exec.Command(exe).CombinedOutput()
+	if err == nil {
+		t.Fatalf("reading a guard page succeeded:\n%s", out)
+	}
+	re := regexp.MustCompile(`guard page violation at \[0x[0-9a-f]+, 0x[0-9a-f]+\) between packages \S+ and x\n`)
+	if !re.Match(out) {
+		t.Errorf("no guard page violation reported:\n%s", out)
+	}
+}
+
+// itabProg is a program whose sandbox in package lookup reads the table of
+// itabs without access to errs, the package that declares many error types.
+// Another sandbox maps errs, which gives it sections of its own.
+const itabProg = `
+package main
+
+import (
+	"errs"
+	"fmt"
+	"gosb"
+	"gosb/backend"
+	"lookup"
+	"strings"
+)
+
+func init() {
+	gosb.Initialize(backend.MPK_BACKEND)
+}
+
+func main() {
+	ok := lookup.Count(&strings.Builder{}, &strings.Reader{})
+	n := 0
+	sandbox ["", ""] () {
+		n = len(errs.All)
+	}()
+	fmt.Println(n, ok)
+}
+`
+
+const lookupPkg = `
+package lookup
+
+import "io"
+
+// Count returns the number of io interfaces implemented by vs.
+func Count(vs ...interface{}) int {
+	ok := 0
+	sandbox ["errs:U", ""] () {
+		for _, v := range vs {
+			if _, b := v.(io.Reader); b {
+				ok++
+			}
+			if _, b := v.(io.Writer); b {
+				ok++
+			}
+			if _, b := v.(io.Seeker); b {
+				ok++
+			}
+			if _, b := v.(io.ReaderAt); b {
+				ok++
+			}
+			if _, b := v.(io.WriterTo); b {
+				ok++
+			}
+			if _, b := v.(io.ByteWriter); b {
+				ok++
+			}
+			if _, b := v.(io.RuneScanner); b {
+				ok++
+			}
+			if _, b := v.(io.StringWriter); b {
+				ok++
+			}
+		}
+	}()
+	return ok
+}
+`
+
+func TestSandboxItabMPK(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	cpuinfo, err := ioutil.ReadFile("/proc/cpuinfo")
+	if err != nil || !strings.Contains(string(cpuinfo), " pku") {
+		t.Skip("skipping test: no support for memory protection keys")
+	}
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestSandboxItabMPK")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Enough itabs for the probe path of the lookup to cross some of them.
+	const n = 300
+	var errs strings.Builder
+	errs.WriteString("package errs\n\nvar All = []error{")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&errs, "&T%d{}, ", i)
+	}
+	errs.WriteString("}\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&errs, "\ntype T%[1]d struct{}\n\nfunc (*T%[1]d) Error() string { return \"t%[1]d\" }\n", i)
+	}
+	exe, _ := gosbBuildPkgs(t, dir, map[string]string{"main": itabProg, "lookup": lookupPkg, "errs": errs.String()}, "")
+	out, err := exec.Command(exe).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	if want := fmt.Sprintf("%d 8\n", n); string(out) != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+}
+
+// rodataProg prints the addresses of a type descriptor that only package x
+// uses, of a type descriptor of x that main uses as well, and of a string
+// literal of both packages.
+const rodataProg = `
+package main
+
+import (
+	"fmt"
+	"gosb"
+	"gosb/backend"
+	"unsafe"
+	"x"
+)
+
+func init() {
+	gosb.Initialize(backend.SIM_BACKEND)
+}
+
+func main() {
+	n := 0
+	sandbox ["x:R", ""] () {
+		n = len(x.Hello())
+	}()
+	var i interface{} = x.Shared{}
+	s := "gosb shared literal"
+	fmt.Println(x.SingleAddr(), (*[2]uintptr)(unsafe.Pointer(&i))[0], (*[2]uintptr)(unsafe.Pointer(&s))[0], n)
+}
+`
+
+const rodataPkg = `
+package x
+
+import "unsafe"
+
+type Single struct{ A int }
+
+type Shared struct{ B int }
+
+//go:noinline
+func SingleAddr() uintptr {
+	var i interface{} = Single{}
+	return (*[2]uintptr)(unsafe.Pointer(&i))[0]
+}
+
+//go:noinline
+func Hello() string {
+	var i interface{} = Shared{}
+	_ = i
+	return "gosb shared literal"
+}
+`
+
+// gosbMapPackages returns the ranges of the sections of each package in the
+// gosb map data.
+func gosbMapPackages(t *testing.T, data []byte) map[string][][2]uint64 {
+	res := make(map[string][][2]uint64)
+	var pkg string
+	for _, l := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(l, "\t") {
+			if l != "packages" && pkg != "" {
+				break
+			}
+			continue
+		}
+		f := strings.Fields(l)
+		if !strings.HasPrefix(l, "\t\t") {
+			pkg = f[0]
+			continue
+		}
+		var r [2]uint64
+		if _, err := fmt.Sscanf(f[1]+" "+f[2], "[%v, %v)", &r[0], &r[1]); err != nil {
+			t.Fatalf("malformed section %q: %v", l, err)
+		}
+		res[pkg] = append(res[pkg], r)
+	}
+	return res
+}
+
+func TestSandboxRodata(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestSandboxRodata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	layout := filepath.Join(dir, "layout")
+	exe, _ := gosbBuildPkgs(t, dir, map[string]string{"main": rodataProg, "x": rodataPkg}, "-gosbmap="+layout)
+	out, err := exec.Command(exe).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	var single, shared, str uint64
+	var n int
+	if _, err := fmt.Sscanf(string(out), "%d %d %d %d\n", &single, &shared, &str, &n); err != nil || n != 19 {
+		t.Fatalf("got %q, want three addresses and 19", out)
+	}
+	data, err := ioutil.ReadFile(layout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs := gosbMapPackages(t, data)
+	in := func(addr uint64, pkg string) bool {
+		for _, r := range pkgs[pkg] {
+			if r[0] <= addr && addr < r[1] {
+				return true
+			}
+		}
+		return false
+	}
+	for _, c := range []struct {
+		what string
+		addr uint64
+		pkg  string
+	}{
+		{"type.x.Single", single, "x"},
+		{"type.x.Shared", shared, "shared-rodata"},
+		{`go.string."gosb shared literal"`, str, "shared-rodata"},
+	} {
+		if !in(c.addr, c.pkg) {
+			t.Errorf("%s at %#x is not in the sections of %s:\n%s", c.what, c.addr, c.pkg, data)
+		}
+	}
+}
+
+const patternProg = `
+package main
+
+import (
+	"fmt"
+	"gosb"
+	"gosb/backend"
+	"x"
+)
+
+func init() {
+	gosb.Initialize(backend.SIM_BACKEND)
+}
+
+func main() {
+	sandbox ["...:R", ""] () {
+		fmt.Println(x.Y())
+	}()
+}
+`
+
+func TestSandboxViewPattern(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestSandboxViewPattern")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pkgs := map[string]string{
+		"main":         patternProg,
+		"x":            "package x\n\nimport \"x/internal/y\"\n\nfunc Y() int { return y.Y }\n",
+		"x/internal/y": "package y\n\nvar Y = 1\n",
+	}
+	layout := filepath.Join(dir, "layout")
+	gosbBuildPkgs(t, dir, pkgs, "-gosbmap="+layout)
+	data, err := ioutil.ReadFile(layout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var view string
+	for _, l := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(l, "\t\tview ") && strings.Contains(l, "fmt:r--") {
+			view = l
+		}
+	}
+	if !strings.Contains(view, " x:r--") {
+		t.Fatalf("... does not cover x in the view of the sandbox:\n%s", data)
+	}
+	// main cannot import x/internal/y, and the runtime and gosb keep their
+	// rights in every sandbox.
+	for _, pkg := range []string{"x/internal/y", "runtime", "internal/cpu", "gosb", "gosb/commons"} {
+		if strings.Contains(view, " "+pkg+":") {
+			t.Errorf("... expands to %s:\n%s", pkg, view)
+		}
+	}
+}
+
+// ownedProg has a //go:sandbox function with nested closures and a method
+// value that nothing else uses.
+const ownedProg = `
+package main
+
+import (
+	"gosb"
+	"gosb/backend"
+	"strings"
+)
+
+func init() {
+	gosb.Initialize(backend.SIM_BACKEND)
+}
+
+//go:sandbox "strings:RW" ""
+func F(s string) int {
+	n := 0
+	up := strings.Map(func(r rune) rune {
+		return []rune(strings.Map(func(r rune) rune {
+			n++
+			return r - 'a' + 'A'
+		}, string(r)))[0]
+	}, s)
+	rep := strings.NewReplacer("T", "TT").Replace
+	return n + len(rep(up))
+}
+
+func main() {
+	println(F("tt"))
+}
+`
+
+func TestSandboxOwnedCode(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestSandboxOwnedCode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	layout := filepath.Join(dir, "layout")
+	exe, _ := gosbBuild(t, dir, ownedProg, "-gosbmap="+layout)
+	out, err := exec.Command(exe).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	if string(out) != "6\n" {
+		t.Errorf("got %q, want %q", out, "6\n")
+	}
+	data, err := ioutil.ReadFile(layout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var view string
+	owned := make(map[string]bool)
+	lines := strings.Split(string(data), "\n")
+	for i, l := range lines {
+		if l == "\t\tfake package main.F" {
+			view = lines[i-2]
+			for _, o := range lines[i+2:] {
+				if !strings.HasPrefix(o, "\t\t\t\t") {
+					break
+				}
+				owned[strings.TrimSpace(o)] = true
+			}
+		}
+	}
+	if view != "\t\tview strings:rw-" {
+		t.Errorf("got %q, want the view of main.F to only hold strings:\n%s", view, data)
+	}
+	for _, s := range []string{"main.F.func1", "main.F.func1.1", "strings.(*Replacer).Replace-fm"} {
+		if !owned[s] {
+			t.Errorf("%s is not in the fake package of main.F:\n%s", s, data)
+		}
+	}
+}
+
+const strictProg = `
+package main
+
+import (
+	"fmt"
+	"gosb"
+	"gosb/backend"
+	"x"
+)
+
+func init() {
+	gosb.Initialize(backend.SIM_BACKEND)
+}
+
+func main() {
+	sandbox "strict" ["", ""] () {
+		fmt.Println(x.Size())
+	}()
+}
+`
+
+func TestSandboxStrict(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestSandboxStrict")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pkgs := map[string]string{
+		"main": strictProg,
+		"x":    "package x\n\nimport \"unsafe\"\n\nfunc Size() uintptr { return unsafe.Sizeof(0) }\n",
+	}
+	// fmt imports unsafe as well, but the standard library is trusted.
+	for _, flag := range []string{"all", "strict", "main.main.func1"} {
+		_, out, err := gosbTryBuildPkgs(t, dir, pkgs, "-sandboxstrict="+flag)
+		if err == nil {
+			t.Errorf("-sandboxstrict=%s: build succeeded", flag)
+			continue
+		}
+		want := "unsafe unsafe not allowed in package x, pulled into strict sandbox main.main.func1 (strict)"
+		if !strings.Contains(out, want) {
+			t.Errorf("-sandboxstrict=%s: missing %q in\n%s", flag, want, out)
+		}
+		if strings.Contains(out, "package fmt") {
+			t.Errorf("-sandboxstrict=%s: rejected the standard library\n%s", flag, out)
+		}
+	}
+	if _, out, err := gosbTryBuildPkgs(t, dir, pkgs, "-sandboxstrict=other"); err == nil || !strings.Contains(out, "no sandbox with id or name other") {
+		t.Errorf("-sandboxstrict=other: %v\n%s", err, out)
+	}
+	gosbBuildPkgs(t, dir, pkgs, "")
+}
+
+const unwindProg = `
+package main
+
+import (
+	"fmt"
+	"gosb"
+	"gosb/backend"
+	"runtime"
+)
+
+func init() {
+	gosb.EnableBenchmarks()
+	gosb.Initialize(backend.SIM_BACKEND)
+}
+
+func panics() (trusted bool) {
+	defer func() {
+		recover()
+		trusted = runtime.GetmSbIds() == ""
+	}()
+	sandbox ["", ""] () {
+		panic("sandbox")
+	}()
+	return false
+}
+
+func exits() bool {
+	done := make(chan bool)
+	go func() {
+		defer func() { done <- runtime.GetmSbIds() == "" }()
+		sandbox ["", ""] () {
+			runtime.Goexit()
+		}()
+	}()
+	return <-done
+}
+
+func main() {
+	fmt.Println(panics(), exits())
+	gosb.DumpBenchmarks()
+}
+`
+
+func TestSandboxUnwind(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestSandboxUnwind")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A panic or a Goexit leaves the sandbox through the runtime, which must
+	// run its epilog exactly once, whether it is deferred or not.
+	for _, gcflags := range []string{"", "-gosbdefer"} {
+		exe, _ := gosbBuildPkgs(t, dir, map[string]string{"main": unwindProg}, "", "-gcflags=main="+gcflags)
+		out, err := exec.Command(exe).CombinedOutput()
+		if err != nil {
+			t.Fatalf("gcflags=%q: %v\n%s", gcflags, err, out)
+		}
+		if !strings.HasPrefix(string(out), "true true\n") {
+			t.Errorf("gcflags=%q: left in a sandbox:\n%s", gcflags, out)
+		}
+		if !strings.Contains(string(out), "prolog: 2 epilog: 2 ") {
+			t.Errorf("gcflags=%q: want 2 prologs and epilogs:\n%s", gcflags, out)
+		}
+	}
+}
+
+const nestedProg = `
+package main
+
+import (
+	"gosb"
+	"gosb/backend"
+)
+
+func init() {
+	gosb.Initialize(backend.SIM_BACKEND)
+}
+
+func main() {
+	sandbox ["", ""] () {
+		sandbox ["", ""] () {}()
+	}()
+}
+`
+
+func TestSandboxNested(t *testing.T) {
+	testenv.MustHaveGoBuild(t)
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "TestSandboxNested")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The inner epilog would return the outer sandbox to the trusted domain.
+	exe, _ := gosbBuild(t, dir, nestedProg, "")
+	out, err := exec.Command(exe).CombinedOutput()
+	if err == nil || !strings.Contains(string(out), "fatal error: nested sandboxes") {
+		t.Errorf("nested sandbox: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,282 @@
+package ld
+
+import (
+	"cmd/internal/objabi"
+	"cmd/link/internal/objfile"
+	"cmd/link/internal/sym"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Constructs that introduce an edge in the call graph of a sandbox.
+const (
+	edgeCall      = "call"
+	edgeFuncValue = "function value"
+	edgeItab      = "interface conversion"
+	edgeType      = "type"
+	edgeIface     = "interface method"
+	edgeReflect   = "reflect method"
+	edgeRef       = "reference"
+)
+
+// sbEdge explains why a symbol is reachable from a sandbox. from is nil for
+// the root.
+type sbEdge struct {
+	from  *sym.Symbol
+	kind  string
+	depth int // number of edges from the root
+}
+
+// sbCallGraph computes the symbols reachable from a sandbox, following
+// relocations like deadcode does. Like deadcode, only the methods of the
+// types reached from the sandbox are considered. The runtime, gosb and their
+// dependencies are not followed.
+type sbCallGraph struct {
+	ctxt        *Link
+	skip        map[string]bool        // packages whose symbols are not followed
+	reached     map[*sym.Symbol]sbEdge // reachable symbols and the edge that reached them
+	queue       []*sym.Symbol          // symbols to flood fill next
+	ifaceMethod map[methodsig]bool     // methods declared in reached interfaces
+	reflect     bool                   // methods might be called via reflection
+}
+
+// gosb_callGraphDeps adds to each sandbox the packages that its closure can
+// reach in the call graph of the binary. The packages found by the compiler
+// only cover what appears in the syntax tree of the closure, which misses
+// interface method calls, function values and methods of foreign types.
+func (ctxt *Link) gosb_callGraphDeps() {
+	if len(objfile.Sandboxes) == 0 {
+		return
+	}
+	// The runtime and its dependencies are mapped in every sandbox. gosb is
+	// the backend, a sandbox must never get its default rights.
+	skip := ctxt.gosb_backendPkgs()
+	methods := ctxt.gosb_allMethods()
+	for i := range objfile.Sandboxes {
+		sb := &objfile.Sandboxes[i]
+		root := ctxt.gosb_sandboxFunc(sb.Func)
+		g := &sbCallGraph{
+			ctxt:        ctxt,
+			skip:        skip,
+			reached:     make(map[*sym.Symbol]sbEdge),
+			ifaceMethod: make(map[methodsig]bool),
+		}
+		g.mark(root, nil, "")
+		g.run(methods)
+		if g.reflect && !*flagSandboxReflect {
+			ctxt.Logf("warning: sandbox %s (%s) uses reflection, the packages of the methods it might call are not mapped without -sandboxreflect\n", sb.Func, sb.Id)
+		}
+
+		pkgs := g.packages(root)
+		known := make(map[string]bool)
+		for _, p := range sb.Packages {
+			known[p] = true
+		}
+		for _, p := range pkgs {
+			if !known[p.name] {
+				sb.Packages = append(sb.Packages, p.name)
+				objfile.SegregatedPkgs[p.name] = true
+			}
+		}
+		if *flagSandboxDeps {
+			g.report(sb.Func, pkgs)
+		}
+	}
+}
+
+// gosb_allMethods returns the methods of all the types in the binary.
+func (ctxt *Link) gosb_allMethods() []methodref {
+	var res []methodref
+	for _, s := range ctxt.Syms.Allsym {
+		if !strings.HasPrefix(s.Name, "type.") || s.Name[5] == '.' || len(s.P) == 0 {
+			continue
+		}
+		var methods []methodref
+		mpos := 0 // 0-3, the R_METHODOFF relocs of runtime.uncommontype
+		for i := range s.R {
+			r := &s.R[i]
+			if r.Type != objabi.R_METHODOFF {
+				continue
+			}
+			if mpos == 0 {
+				methods = append(methods, methodref{src: s})
+			}
+			methods[len(methods)-1].r[mpos] = r
+			mpos = (mpos + 1) % len(methodref{}.r)
+		}
+		if len(methods) == 0 {
+			continue
+		}
+		methodsigs := decodetypeMethods(ctxt.Arch, s)
+		if len(methods) != len(methodsigs) {
+			panic(fmt.Sprintf("%q has %d method relocations for %d methods", s.Name, len(methods), len(methodsigs)))
+		}
+		for i, m := range methodsigs {
+			methods[i].m = m
+		}
+		res = append(res, methods...)
+	}
+	return res
+}
+
+// mark records that s is reachable from parent through a construct of
+// the given kind. Edges are only recorded from reached parents, so that
+// following them always ends at the root.
+func (g *sbCallGraph) mark(s, parent *sym.Symbol, kind string) {
+	if s == nil {
+		return
+	}
+	s = resolveABIAlias(s)
+	if _, ok := g.reached[s]; ok {
+		return
+	}
+	e := sbEdge{kind: kind}
+	if p, ok := g.reached[parent]; ok && parent != nil {
+		e.from, e.depth = parent, p.depth+1
+	}
+	g.reached[s] = e
+	if s.Attr.ReflectMethod() {
+		g.reflect = true
+	}
+	if !g.skip[s.File] {
+		g.queue = append(g.queue, s)
+	}
+}
+
+// run floods the call graph until no new method becomes reachable.
+// The exported methods that reflection might call are only followed with
+// -sandboxreflect, as they cover most of the binary.
+func (g *sbCallGraph) run(methods []methodref) {
+	callSym := g.ctxt.Syms.ROLookup("reflect.Value.Call", sym.SymVerABIInternal)
+	methSym := g.ctxt.Syms.ROLookup("reflect.Value.Method", sym.SymVerABIInternal)
+	for {
+		g.flood()
+		if _, ok := g.reached[callSym]; ok && callSym != nil {
+			g.reflect = true
+		}
+		if _, ok := g.reached[methSym]; ok && methSym != nil {
+			g.reflect = true
+		}
+		for _, m := range methods {
+			if _, ok := g.reached[m.src]; !ok {
+				continue
+			}
+			kind := edgeIface
+			if !g.ifaceMethod[m.m] {
+				if !*flagSandboxReflect || !g.reflect || !m.isExported() {
+					continue
+				}
+				kind = edgeReflect
+			}
+			for _, r := range m.r {
+				g.mark(r.Sym, m.src, kind)
+			}
+		}
+		if len(g.queue) == 0 {
+			return
+		}
+	}
+}
+
+// flood follows the relocations of the symbols in the queue.
+func (g *sbCallGraph) flood() {
+	for len(g.queue) > 0 {
+		s := g.queue[0]
+		g.queue = g.queue[1:]
+		if strings.HasPrefix(s.Name, "type.") && s.Name[5] != '.' && len(s.P) > 0 {
+			if decodetypeKind(g.ctxt.Arch, s)&kindMask == kindInterface {
+				for _, sig := range decodeIfaceMethods(g.ctxt.Arch, s) {
+					g.ifaceMethod[sig] = true
+				}
+			}
+		}
+		for i := range s.R {
+			r := &s.R[i]
+			// Methods are handled in run, regardless of the type they
+			// are attached to.
+			if r.Sym == nil || r.Type == objabi.R_WEAKADDROFF || r.Type == objabi.R_METHODOFF {
+				continue
+			}
+			g.mark(r.Sym, s, edgeKind(r))
+		}
+		if s.FuncInfo != nil {
+			for _, fd := range s.FuncInfo.Funcdata {
+				g.mark(fd, s, edgeRef)
+			}
+		}
+		g.mark(s.Gotype, s, edgeType)
+		g.mark(s.Sub, s, edgeRef)
+		g.mark(s.Outer, s, edgeRef)
+	}
+}
+
+// edgeKind returns the construct that introduced the relocation r.
+func edgeKind(r *sym.Reloc) string {
+	t := resolveABIAlias(r.Sym)
+	switch {
+	case r.Type.IsDirectJump() || r.Type == objabi.R_CALLIND:
+		return edgeCall
+	case strings.HasPrefix(t.Name, "go.itab."):
+		return edgeItab
+	case strings.HasPrefix(t.Name, "type."):
+		return edgeType
+	case t.Type == sym.STEXT || strings.HasSuffix(t.Name, "·f"):
+		return edgeFuncValue
+	}
+	return edgeRef
+}
+
+// sbPackage is a package reachable from a sandbox, along with the first
+// symbol of that package that was reached.
+type sbPackage struct {
+	name string
+	s    *sym.Symbol
+}
+
+// packages returns the packages reachable from the sandbox root, sorted by
+// name. The runtime, its dependencies and packages that are not part of the
+// binary are omitted.
+func (g *sbCallGraph) packages(root *sym.Symbol) []sbPackage {
+	first := make(map[string]*sym.Symbol)
+	for s := range g.reached {
+		if s == root || g.skip[s.File] {
+			continue
+		}
+		if _, ok := g.ctxt.PackageDecl[s.File]; !ok {
+			continue
+		}
+		// Keep the symbol closest to the root, for shorter reports.
+		d := g.reached[s].depth
+		if f, ok := first[s.File]; !ok || d < g.reached[f].depth || (d == g.reached[f].depth && s.Name < f.Name) {
+			first[s.File] = s
+		}
+	}
+	res := make([]sbPackage, 0, len(first))
+	for p, s := range first {
+		res = append(res, sbPackage{p, s})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].name < res[j].name })
+	return res
+}
+
+// report prints, for each package reachable from the sandbox, the chain of
+// edges that reached it.
+func (g *sbCallGraph) report(name string, pkgs []sbPackage) {
+	fmt.Printf("sandbox %s\n", name)
+	for _, p := range pkgs {
+		fmt.Printf("\t%s\n", p.name)
+		var chain []string
+		for s := p.s; ; {
+			e := g.reached[s]
+			if e.from == nil {
+				break
+			}
+			chain = append(chain, fmt.Sprintf("\t\t%s -> %s (%s)", e.from.Name, s.Name, e.kind))
+			s = e.from
+		}
+		for i := len(chain) - 1; i >= 0; i-- {
+			fmt.Println(chain[i])
+		}
+	}
+}
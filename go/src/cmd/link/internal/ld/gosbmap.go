@@ -0,0 +1,123 @@
+package ld
+
+import (
+	"bufio"
+	"cmd/link/internal/sym"
+	"fmt"
+	lb "gosb/commons"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+// gosb_writeMap writes to the file given by -gosbmap a description of the
+// bloated packages and of the sandbox domains, i.e., the content of the
+// .bloated and .sandboxes sections along with the fake packages that the
+// gosb runtime creates for the sandbox functions.
+// It must be called once the sections have been laid out.
+func (ctxt *Link) gosb_writeMap() {
+	if *flagGosbMap == "" || !HasSandboxes() {
+		return
+	}
+	f, err := os.Create(*flagGosbMap)
+	if err != nil {
+		Exitf("cannot create gosb map: %v", err)
+	}
+	w := bufio.NewWriter(f)
+
+	pkgs := make([]*lb.Package, 0, len(Bloats)+1)
+	for _, p := range Bloats {
+		pkgs = append(pkgs, p)
+	}
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].Name < pkgs[j].Name })
+	pkgs = append(pkgs, nonbloat)
+	fmt.Fprintf(w, "packages\n")
+	for _, p := range pkgs {
+		fmt.Fprintf(w, "\t%s id=%d\n", p.Name, p.Id)
+		for i, s := range p.Sects {
+			if s.Size == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "\t\t%-16s %s %s\n", sym.SymKind(i), gosb_mapRange(s.Addr, s.Size), gosb_mapProt(s.Prot))
+		}
+	}
+
+	if len(guards) > 0 {
+		fmt.Fprintf(w, "guards\n")
+		for _, g := range gosb_guards() {
+			fmt.Fprintf(w, "\t%s %s | %s\n", gosb_mapRange(g.Addr, g.Size), g.Before, g.After)
+		}
+	}
+
+	fmt.Fprintf(w, "sandboxes\n")
+	for _, d := range domains {
+		fmt.Fprintf(w, "\t%s func=%s\n", d.Id, d.Func)
+		classes, extras := lb.DecodeSyscalls(d.Sys)
+		fmt.Fprintf(w, "\t\tsyscalls %s", strings.Join(classes, ","))
+		for _, e := range extras {
+			fmt.Fprintf(w, " +%d", e)
+		}
+		fmt.Fprintf(w, "\n")
+		view := make([]string, 0, len(d.View))
+		for p, prot := range d.View {
+			view = append(view, p+":"+gosb_mapProt(prot))
+		}
+		sort.Strings(view)
+		fmt.Fprintf(w, "\t\tview %s\n", strings.Join(view, " "))
+		dpkgs := append([]string(nil), d.Pkgs...)
+		sort.Strings(dpkgs)
+		fmt.Fprintf(w, "\t\tpackages %s\n", strings.Join(dpkgs, " "))
+		if d.Id != "-1" {
+			ctxt.gosb_mapFakePackage(w, d.Func)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatalf("writing gosb map: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatalf("writing gosb map: %v", err)
+	}
+}
+
+// gosb_mapFakePackage describes the fake package that the gosb runtime
+// creates for the sandbox function name: its code and stack objects, along
+// with those of main.main. The ranges match the ones the runtime maps.
+func (ctxt *Link) gosb_mapFakePackage(w *bufio.Writer, name string) {
+	f := ctxt.gosb_sandboxFunc(name)
+	fmt.Fprintf(w, "\t\tfake package %s\n", name)
+	size := gosb_sandboxSize(f)
+	fmt.Fprintf(w, "\t\t\t%-16s %s %s\n", f.Name, gosb_mapRange(uint64(f.Value), uint64(size)), gosb_mapProt(lb.X_VAL|lb.R_VAL|lb.USER_VAL))
+	for _, s := range sandOwned[f.Name] {
+		fmt.Fprintf(w, "\t\t\t\t%s\n", s.Name)
+	}
+	for _, n := range []string{name + ".stkobj", "main.main.stkobj"} {
+		if s := ctxt.Syms.ROLookup(n, 0); s != nil {
+			fmt.Fprintf(w, "\t\t\t%-16s %s %s\n", n, gosb_mapRange(uint64(s.Value), uint64(s.Size)), gosb_mapProt(lb.R_VAL|lb.USER_VAL))
+		}
+	}
+}
+
+// gosb_mapRange formats the page-aligned range covering [addr, addr+size).
+func gosb_mapRange(addr, size uint64) string {
+	return fmt.Sprintf("[%#x, %#x)", lb.Round(addr, false), lb.Round(addr, false)+lb.Round(size, true))
+}
+
+// gosb_mapProt formats a protection as rwx, followed by a u for pages
+// accessible to the user.
+func gosb_mapProt(prot uint8) string {
+	b := []byte("---")
+	if prot&lb.R_VAL != 0 {
+		b[0] = 'r'
+	}
+	if prot&lb.W_VAL != 0 {
+		b[1] = 'w'
+	}
+	if prot&lb.X_VAL != 0 {
+		b[2] = 'x'
+	}
+	if prot&lb.USER_VAL != 0 {
+		b = append(b, 'u')
+	}
+	return string(b)
+}
@@ -0,0 +1,31 @@
+package ld
+
+import (
+	lb "gosb/commons"
+	"io/ioutil"
+)
+
+// gosb_loadPolicy reads the privilege ceilings of the policy file given by
+// -gosbpolicy, see gosb/commons/policy.go. It returns nil without a policy.
+func gosb_loadPolicy() *lb.Policy {
+	if *flagGosbPolicy == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(*flagGosbPolicy)
+	if err != nil {
+		Exitf("-gosbpolicy: %v", err)
+	}
+	policy, err := lb.ParsePolicy(data)
+	if err != nil {
+		Exitf("%s: %v", *flagGosbPolicy, err)
+	}
+	return policy
+}
+
+// gosb_checkPolicy reports the privileges of the sandbox sb, declared in
+// package pkg, that exceed the ceilings of the policy.
+func gosb_checkPolicy(policy *lb.Policy, sb *lb.SandboxDomain, pkg string) {
+	for _, e := range policy.Check(sb, pkg) {
+		Errorf(nil, "sandbox %s (%s): %s", sb.Func, sb.Id, e)
+	}
+}
@@ -0,0 +1,54 @@
+package ld
+
+import (
+	"cmd/link/internal/objfile"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// gosb_checkStrict rejects the unsafe constructs, i.e., imports of unsafe,
+// //go:linkname directives, assembly and cgo, found in the packages of the
+// sandboxes selected by -sandboxstrict. Such constructs can bypass the memory
+// view of the sandbox, e.g., by calling into the runtime directly.
+func (ctxt *Link) gosb_checkStrict() {
+	if *flagSandboxStrict == "" {
+		return
+	}
+	strict := make(map[string]bool)
+	for _, s := range strings.Split(*flagSandboxStrict, ",") {
+		strict[strings.TrimSpace(s)] = true
+	}
+	all := strict["all"]
+	matched := make(map[string]bool)
+	for _, d := range domains {
+		// Sandbox ids are quoted, as in the source.
+		id := d.Id
+		if u, err := strconv.Unquote(id); err == nil {
+			id = u
+		}
+		if id == "-1" || (!all && !strict[id] && !strict[d.Func]) {
+			continue
+		}
+		matched[id], matched[d.Func] = true, true
+		pkgs := append([]string(nil), d.Pkgs...)
+		sort.Strings(pkgs)
+		for _, p := range pkgs {
+			for _, u := range objfile.UnsafeUses[p] {
+				pos := ""
+				if u.Pos != "-" {
+					pos = u.Pos + ": "
+				}
+				Errorf(nil, "%s%s %s not allowed in package %s, pulled into strict sandbox %s (%s)", pos, u.Kind, u.Sym, p, d.Func, id)
+			}
+		}
+	}
+	for s := range strict {
+		if s != "all" && !matched[s] {
+			Errorf(nil, "-sandboxstrict: no sandbox with id or name %s", s)
+		}
+	}
+	if nerrors > 0 {
+		errorexit()
+	}
+}
@@ -2338,7 +2338,8 @@ func genasmsym(ctxt *Link, put func(*Link, *sym.Symbol, string, SymbolType, int6
 			sym.SRODATARELRO,
 			sym.STYPELINK,
 			sym.SITABLINK,
-			sym.SWINDOWS:
+			sym.SWINDOWS,
+			sym.SBLOAT:
 			if !s.Attr.Reachable() {
 				continue
 			}
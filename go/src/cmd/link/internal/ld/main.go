@@ -59,10 +59,16 @@ var (
 	flagOutfile    = flag.String("o", "", "write output to `file`")
 	flagPluginPath = flag.String("pluginpath", "", "full path name for plugin")
 
-	flagInstallSuffix = flag.String("installsuffix", "", "set package directory `suffix`")
-	flagDumpDep       = flag.Bool("dumpdep", false, "dump symbol dependency graph")
-	flagRace          = flag.Bool("race", false, "enable race detector")
-	flagMsan          = flag.Bool("msan", false, "enable MSan interface")
+	flagInstallSuffix  = flag.String("installsuffix", "", "set package directory `suffix`")
+	flagDumpDep        = flag.Bool("dumpdep", false, "dump symbol dependency graph")
+	flagGosbMap        = flag.String("gosbmap", "", "write the sandbox layout of the binary to `file`")
+	flagGosbGuard      = flag.Bool("gosbguard", false, "insert guard pages between the sections of bloated packages")
+	flagGosbPolicy     = flag.String("gosbpolicy", "", "reject sandboxes that exceed the privilege ceilings of the policy `file`")
+	flagSandboxDeps    = flag.Bool("sandboxdeps", false, "report the packages reachable from each sandbox")
+	flagSandboxReflect = flag.Bool("sandboxreflect", false, "let sandboxes that use reflection reach the exported methods of every type")
+	flagSandboxStrict  = flag.String("sandboxstrict", "", "reject unsafe, linkname, assembly and cgo in the packages of the `sandboxes` (comma-separated ids or names, or all)")
+	flagRace           = flag.Bool("race", false, "enable race detector")
+	flagMsan           = flag.Bool("msan", false, "enable MSan interface")
 
 	flagFieldTrack = flag.String("k", "", "set field tracking `symbol`")
 	flagLibGCC     = flag.String("libgcc", "", "compiler support lib for internal linking; use \"none\" to disable")
@@ -248,6 +254,8 @@ func Main(arch *sys.Arch, theArch Arch) {
 	// It also extracts the stmp_* symbols that are not inside bloated packages
 	// and relocates them into a shared package
 	ctxt.fixingStupidSymbols()
+	// @aghosn, split the pooled read-only data between packages.
+	ctxt.gosb_attributeRodata()
 	ctxt.dodata()
 	order := ctxt.address()
 
@@ -256,6 +264,7 @@ func Main(arch *sys.Arch, theArch Arch) {
 
 	// @aghosn, dump the content of sandboxes.
 	ctxt.dumpGosbSections(order, &filesize)
+	ctxt.gosb_writeMap()
 
 	// Write out the output file.
 	// It is split into two parts (Asmb and Asmb2). The first
@@ -706,6 +706,9 @@ func (ctxt *Link) symtab() {
 		lastmoduledatap.Size = 0 // overwrite existing value
 		lastmoduledatap.AddAddr(ctxt.Arch, moduledata)
 	}
+
+	// @aghosn, tell gosb where its sections are loaded.
+	ctxt.gosb_defineSections()
 }
 
 func isStaticTemp(name string) bool {
@@ -1,10 +1,9 @@
 package objfile
 
 import (
+	"cmd/link/internal/sym"
 	gosb "gosb/commons"
-	"io/ioutil"
-	"strconv"
-	"strings"
+	"log"
 )
 
 type SBObjEntry struct {
@@ -15,18 +14,24 @@ type SBObjEntry struct {
 	Packages []string
 	Extras   []gosb.Entry
 	Pristine bool
+	Pkg      string // package that declares the sandbox
 }
 
-const (
-	sandboxheader = "\xeegosandx\n"
-	sandboxfooter = "\xefgosandx\n"
-)
+// UnsafeUse is a construct that lets a package escape the memory view of a
+// sandbox, as recorded by the compiler.
+type UnsafeUse struct {
+	Kind string // "unsafe", "linkname", "asm" or "cgo"
+	Pos  string // "-" when unknown
+	Sym  string
+}
 
 // Sandboxes we parsed by looking at object files
 var (
 	Sandboxes      []SBObjEntry
 	SBMap          map[string]*SBObjEntry
 	SegregatedPkgs map[string]bool
+	// UnsafeUses maps package paths to their unsafe constructs.
+	UnsafeUses map[string][]UnsafeUse
 )
 
 func assert(cond bool, msg string) {
@@ -42,68 +47,61 @@ func registerPackages(pkgs []string) {
 	}
 }
 
-// readSandboxObj parses an object file to gather sandbox information.
+// readGosbSym decodes the sandboxes and unsafe constructs that the compiler
+// recorded in the SGOSB symbol s of package pkg.
 // We accumulate this information inside the above global variables.
-func readSandboxObj(path string) {
-	// Get the entire data.
-	data, err := ioutil.ReadFile(path)
-	assert(err == nil, "Error reading file")
-	file := string(data)
-	content := strings.Split(file, sandboxheader)
-	// filter only the sandboxes entries
-	var sbs []string = nil
-	for i, v := range content {
-		if i%2 == 1 {
-			assert(strings.Contains(v, sandboxfooter), "Malformed sandbox entry: missing footer")
-			split := strings.Split(v, sandboxfooter)
-			assert(len(split) <= 2, "Malformed sandbox entry: more than two elements in split")
-			if len(split[0]) > 0 {
-				sbs = append(sbs, split[0])
-			}
+func readGosbSym(s *sym.Symbol, pkg string) {
+	sbs, uses, err := gosb.DecodeObject(s.P)
+	if err != nil {
+		log.Fatalf("%s: %v", s.File, err)
+	}
+	if len(uses) > 0 {
+		if UnsafeUses == nil {
+			UnsafeUses = make(map[string][]UnsafeUse)
+		}
+		for _, u := range uses {
+			UnsafeUses[pkg] = append(UnsafeUses[pkg], UnsafeUse{u.Kind, u.Pos, u.Sym})
 		}
 	}
 	if len(sbs) > 0 {
-		registerSandboxes(sbs)
+		registerSandboxes(pkg, sbs)
 	}
 }
 
-func registerSandboxes(sbs []string) {
+// checkUniqueId makes sure that sandbox ids, whether generated or named by
+// the user, identify a single sandbox in the binary.
+func checkUniqueId(name, id string) {
+	for _, sb := range Sandboxes {
+		if sb.Id == id {
+			log.Fatalf("duplicate sandbox id %v: used by %v and %v", id, sb.Func, name)
+		}
+	}
+}
+
+func registerSandboxes(pkg string, sbs []gosb.ObjSandbox) {
 	if SegregatedPkgs == nil {
 		SegregatedPkgs = make(map[string]bool)
 		SBMap = make(map[string]*SBObjEntry)
 	}
 	for _, v := range sbs {
-		contents := strings.Split(v, "\n")
-		assert(len(contents) > 0, "Empty sandbox entry")
-		size, err := strconv.Atoi(contents[0])
-		assert(err == nil, "error parsing initial size")
-		assert(size > 0, "Malformed sandbox entry")
-		contents = contents[1:]
-		for i := 0; i < size; i++ {
-			content := contents
-			name, content := content[0], content[1:]
-			assert(len(name) > 0, "Empty sandbox name")
-			config, content := strings.Split(content[0], ";"), content[1:]
-			assert(len(config) == 3, "Malformed configuration")
-			nbPkgs, err := strconv.Atoi(content[0])
-			assert(err == nil, "Error parsing number of packages")
-			content = content[1:]
-			// Parse memory view
-			extras, pristine, err := gosb.ParseMemoryView(config[1])
-			if err != nil {
-				panic(err.Error())
-			}
-			pkgs := make([]string, nbPkgs)
-			copy(pkgs, content)
-			content = content[nbPkgs:]
-			Sandboxes = append(Sandboxes, SBObjEntry{name, config[0], config[1], config[2], pkgs, extras, pristine})
-			// Finally add these packages to the ones that need to be bloated
-			for _, e := range extras {
+		assert(len(v.Func) > 0, "Empty sandbox name")
+		// Parse memory view
+		extras, pristine, err := gosb.ParseMemoryView(v.Mem)
+		if err != nil {
+			panic(err.Error())
+		}
+		pkgs := make([]string, len(v.Packages))
+		copy(pkgs, v.Packages)
+		checkUniqueId(v.Func, v.Id)
+		Sandboxes = append(Sandboxes, SBObjEntry{v.Func, v.Id, v.Mem, v.Sys, pkgs, extras, pristine, pkg})
+		// Finally add these packages to the ones that need to be bloated.
+		// Patterns are expanded once all the packages are known.
+		for _, e := range extras {
+			if !gosb.IsPackagePattern(e.Name) {
 				pkgs = append(pkgs, e.Name)
 			}
-			SBMap[name] = &Sandboxes[len(Sandboxes)-1]
-			registerPackages(pkgs)
-			contents = content
 		}
+		SBMap[v.Func] = &Sandboxes[len(Sandboxes)-1]
+		registerPackages(pkgs)
 	}
 }
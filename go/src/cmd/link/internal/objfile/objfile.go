@@ -184,8 +184,6 @@ func (r *objReader) loadObjFile() {
 	if string(buf[:]) != endmagic {
 		log.Fatalf("%s: invalid file end", r.pn)
 	}
-	// Check if there is more to read, if not, return
-	readSandboxObj(r.rd.File().Name())
 }
 
 func (r *objReader) readSlices() {
@@ -291,6 +289,10 @@ overwrite:
 	}
 	s.P = data
 	s.Attr.Set(sym.AttrReadOnly, r.dataReadOnly)
+	if s.Type == sym.SGOSB {
+		// @aghosn, sandbox information of the package.
+		readGosbSym(s, r.lib.Pkg)
+	}
 	if nreloc > 0 {
 		s.R = r.reloc[:nreloc:nreloc]
 		if !isdup {
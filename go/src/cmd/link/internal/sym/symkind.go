@@ -117,6 +117,10 @@ const (
 
 	// @aghosn we use this to fake our segment
 	SBLOAT
+
+	// @aghosn sandbox information from the object files (never appears in
+	// the output)
+	SGOSB
 )
 
 // AbiSymKindToSymKind maps values read from object files (which are
@@ -135,6 +139,7 @@ var AbiSymKindToSymKind = [...]SymKind{
 	SDWARFLOC,
 	SDWARFLINES,
 	SABIALIAS,
+	SGOSB,
 }
 
 // ReadOnly are the symbol kinds that form read-only sections. In some
@@ -262,6 +262,19 @@ type (
 		Body *BlockStmt // function body
 	}
 
+	// A SandboxLit node represents a sandbox literal, i.e., a function
+	// literal whose execution is restricted to a memory view and a set
+	// of syscall classes.
+	SandboxLit struct {
+		Sandbox token.Pos // position of "sandbox" keyword
+		Name    *BasicLit // sandbox name; or nil
+		Lbrack  token.Pos // position of "["
+		Mem     *BasicLit // memory view
+		Sys     *BasicLit // syscall classes
+		Rbrack  token.Pos // position of "]"
+		Func    *FuncLit  // sandboxed function; Func.Type.Func is invalid
+	}
+
 	// A CompositeLit node represents a composite literal.
 	CompositeLit struct {
 		Type       Expr      // literal type; or nil
@@ -420,11 +433,12 @@ type (
 
 // Pos and End implementations for expression/type nodes.
 
-func (x *BadExpr) Pos() token.Pos  { return x.From }
-func (x *Ident) Pos() token.Pos    { return x.NamePos }
-func (x *Ellipsis) Pos() token.Pos { return x.Ellipsis }
-func (x *BasicLit) Pos() token.Pos { return x.ValuePos }
-func (x *FuncLit) Pos() token.Pos  { return x.Type.Pos() }
+func (x *BadExpr) Pos() token.Pos    { return x.From }
+func (x *Ident) Pos() token.Pos      { return x.NamePos }
+func (x *Ellipsis) Pos() token.Pos   { return x.Ellipsis }
+func (x *BasicLit) Pos() token.Pos   { return x.ValuePos }
+func (x *FuncLit) Pos() token.Pos    { return x.Type.Pos() }
+func (x *SandboxLit) Pos() token.Pos { return x.Sandbox }
 func (x *CompositeLit) Pos() token.Pos {
 	if x.Type != nil {
 		return x.Type.Pos()
@@ -463,6 +477,7 @@ func (x *Ellipsis) End() token.Pos {
 }
 func (x *BasicLit) End() token.Pos       { return token.Pos(int(x.ValuePos) + len(x.Value)) }
 func (x *FuncLit) End() token.Pos        { return x.Body.End() }
+func (x *SandboxLit) End() token.Pos     { return x.Func.End() }
 func (x *CompositeLit) End() token.Pos   { return x.Rbrace + 1 }
 func (x *ParenExpr) End() token.Pos      { return x.Rparen + 1 }
 func (x *SelectorExpr) End() token.Pos   { return x.Sel.End() }
@@ -494,6 +509,7 @@ func (*Ident) exprNode()          {}
 func (*Ellipsis) exprNode()       {}
 func (*BasicLit) exprNode()       {}
 func (*FuncLit) exprNode()        {}
+func (*SandboxLit) exprNode()     {}
 func (*CompositeLit) exprNode()   {}
 func (*ParenExpr) exprNode()      {}
 func (*SelectorExpr) exprNode()   {}
@@ -97,6 +97,14 @@ func Walk(v Visitor, node Node) {
 		Walk(v, n.Type)
 		Walk(v, n.Body)
 
+	case *SandboxLit:
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+		Walk(v, n.Mem)
+		Walk(v, n.Sys)
+		Walk(v, n.Func)
+
 	case *CompositeLit:
 		if n.Type != nil {
 			Walk(v, n.Type)
@@ -917,17 +917,7 @@ func (p *parser) parseFuncType() (*ast.FuncType, *ast.Scope) {
 	if p.trace {
 		defer un(trace(p, "FuncType"))
 	}
-	pos := p.pos
-	if p.tok == token.SANDBOX {
-		p.next() // Consume `sandbox`
-		p.next() // Consume `[`
-		p.next() // Consume `""`
-		p.next() // Consume `,`
-		p.next() // Consume `""`
-		p.next() // Consume `]`
-	} else {
-		pos = p.expect(token.FUNC)
-	}
+	pos := p.expect(token.FUNC)
 	scope := ast.NewScope(p.topScope) // function scope
 	params, results := p.parseSignature(scope)
 
@@ -1131,6 +1121,54 @@ func (p *parser) parseFuncTypeOrLit() ast.Expr {
 	return &ast.FuncLit{Type: typ, Body: body}
 }
 
+// SandboxLit = "sandbox" [ string_lit ] "[" string_lit "," string_lit "]" Signature FuncBody .
+func (p *parser) parseSandboxLit() *ast.SandboxLit {
+	if p.trace {
+		defer un(trace(p, "SandboxLit"))
+	}
+
+	pos := p.expect(token.SANDBOX)
+	var name *ast.BasicLit
+	if p.tok == token.STRING {
+		name = p.parseSandboxConfig()
+	}
+	lbrack := p.expect(token.LBRACK)
+	mem := p.parseSandboxConfig()
+	p.expect(token.COMMA)
+	sys := p.parseSandboxConfig()
+	rbrack := p.expect(token.RBRACK)
+
+	scope := ast.NewScope(p.topScope) // function scope
+	params, results := p.parseSignature(scope)
+	typ := &ast.FuncType{Func: token.NoPos, Params: params, Results: results}
+
+	p.exprLev++
+	body := p.parseBody(scope)
+	p.exprLev--
+
+	return &ast.SandboxLit{
+		Sandbox: pos,
+		Name:    name,
+		Lbrack:  lbrack,
+		Mem:     mem,
+		Sys:     sys,
+		Rbrack:  rbrack,
+		Func:    &ast.FuncLit{Type: typ, Body: body},
+	}
+}
+
+// parseSandboxConfig parses one of the string literals configuring a sandbox.
+func (p *parser) parseSandboxConfig() *ast.BasicLit {
+	x := &ast.BasicLit{ValuePos: p.pos, Kind: token.STRING, Value: `""`}
+	if p.tok == token.STRING {
+		x.Value = p.lit
+	} else {
+		p.errorExpected(p.pos, "string literal")
+	}
+	p.next() // make progress
+	return x
+}
+
 // parseOperand may return an expression or a raw type (incl. array
 // types of the form [...]T. Callers must verify the result.
 // If lhs is set and the result is an identifier, it is not resolved.
@@ -1166,7 +1204,7 @@ func (p *parser) parseOperand(lhs bool) ast.Expr {
 		return p.parseFuncTypeOrLit()
 
 	case token.SANDBOX:
-		return p.parseFuncTypeOrLit()
+		return p.parseSandboxLit()
 	}
 
 	if typ := p.tryIdentOrType(); typ != nil {
@@ -1380,6 +1418,7 @@ func (p *parser) checkExpr(x ast.Expr) ast.Expr {
 	case *ast.Ident:
 	case *ast.BasicLit:
 	case *ast.FuncLit:
+	case *ast.SandboxLit:
 	case *ast.CompositeLit:
 	case *ast.ParenExpr:
 		panic("unreachable")
@@ -48,6 +48,9 @@ var valids = []string{
 	`package p; var _ = map[*P]int{&P{}:0, {}:1}`,
 	`package p; type T = int`,
 	`package p; type (T = p.T; _ = struct{}; x = *T)`,
+	`package p; func f() { sandbox["main:R", "io"]() {}() }`,
+	`package p; func f() { _ = sandbox "decode" ["", ""](x int) int { return x } }`,
+	`package p; func f() { go sandbox["", ""]() {}() }`,
 }
 
 func TestValid(t *testing.T) {
@@ -127,6 +130,12 @@ var invalids = []string{
 	// issue 13475
 	`package p; func f() { if true {} else ; /* ERROR "expected if statement or block" */ }`,
 	`package p; func f() { if true {} else defer /* ERROR "expected if statement or block" */ f() }`,
+
+	// sandbox literals
+	`package p; func f() { _ = sandbox[main /* ERROR "expected string literal" */ , ""]() {} }`,
+	`package p; func f() { _ = sandbox["", ""] { /* ERROR "expected '\('" */ } }`,
+	`package p; func f() { _ = sandbox 42 /* ERROR "expected '\['" */ ["", ""]() {} }`,
+	`package p; func f() { _ = sandbox["", ""]() 0 /* ERROR "expected '{'" */ }`,
 }
 
 func TestInvalid(t *testing.T) {
@@ -800,6 +800,22 @@ func (p *printer) expr1(expr ast.Expr, prec1, depth int) {
 		p.signature(x.Type.Params, x.Type.Results)
 		p.funcBody(p.distanceFrom(x.Type.Pos(), startCol), blank, x.Body)
 
+	case *ast.SandboxLit:
+		p.print(x.Sandbox, token.SANDBOX)
+		startCol := p.out.Column - len("sandbox")
+		if x.Name != nil {
+			p.print(blank)
+			p.expr(x.Name)
+			p.print(blank)
+		}
+		p.print(x.Lbrack, token.LBRACK)
+		p.expr(x.Mem)
+		p.print(token.COMMA, blank)
+		p.expr(x.Sys)
+		p.print(x.Rbrack, token.RBRACK)
+		p.signature(x.Func.Type.Params, x.Func.Type.Results)
+		p.funcBody(p.distanceFrom(x.Sandbox, startCol), blank, x.Func.Body)
+
 	case *ast.ParenExpr:
 		if _, hasParens := x.X.(*ast.ParenExpr); hasParens {
 			// don't print parentheses around an already parenthesized expression
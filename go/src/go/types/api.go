@@ -213,6 +213,10 @@ type Info struct {
 	//
 	Scopes map[ast.Node]*Scope
 
+	// Sandboxes maps sandbox literals to their description, including
+	// the variables they capture from enclosing functions.
+	Sandboxes map[*ast.SandboxLit]*Sandbox
+
 	// InitOrder is the list of package-level initializers in the order in which
 	// they must be executed. Initializers referring to variables related by an
 	// initialization dependency appear in topological order, the others appear
@@ -14,6 +14,7 @@ import (
 	"internal/testenv"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 	"testing"
 
@@ -1468,3 +1469,59 @@ func f(x T) T { return foo.F(x) }
 		}
 	}
 }
+
+func TestSandboxesInfo(t *testing.T) {
+	const src = `
+package p
+
+var global int
+
+func f(a, b int) {
+	c := 0
+	s := sandbox "outer" ["main:R", "io"](d int) {
+		print(global, b, d)
+		_ = sandbox["", ""]() {
+			c = a + b + d
+		}
+	}
+	s(c)
+}
+`
+	info := &Info{Sandboxes: make(map[*ast.SandboxLit]*Sandbox)}
+	mustTypecheck(t, "p", src, info)
+
+	var got []string
+	for _, sb := range info.Sandboxes {
+		var captured []string
+		for _, v := range sb.Captured {
+			captured = append(captured, v.Name())
+		}
+		got = append(got, fmt.Sprintf("%q %q %q %v", sb.Name, sb.Mem, sb.Sys, captured))
+	}
+	sort.Strings(got)
+	want := []string{
+		`"" "" "" [a b d c]`,
+		`"outer" "main:R" "io" [b a c]`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSandboxConfigInfo(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "src", `package p; var _ = sandbox["", ""]() {}`, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The parser only accepts string literals, patch the AST instead.
+	lit := f.Decls[0].(*ast.GenDecl).Specs[0].(*ast.ValueSpec).Values[0].(*ast.SandboxLit)
+	lit.Mem.Kind, lit.Mem.Value = token.INT, "42"
+
+	var errs []error
+	conf := Config{Error: func(err error) { errs = append(errs, err) }}
+	conf.Check("p", fset, []*ast.File{f}, nil)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "sandbox memory view must be an untyped string constant") {
+		t.Errorf("got errors %v, want a single sandbox memory view error", errs)
+	}
+}
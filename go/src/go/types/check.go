@@ -93,6 +93,8 @@ type Checker struct {
 	finals   []func()              // list of final actions; processed at the end of type-checking the current set of files
 	objPath  []Object              // path of object dependencies during type inference (for cycle reporting)
 
+	sandboxes map[*Scope]*Sandbox // maps function scopes of sandbox literals to their description
+
 	// context within which the current object is type-checked
 	// (valid only for the duration of type-checking a specific object)
 	context
@@ -205,6 +207,7 @@ func (check *Checker) initFiles(files []*ast.File) {
 	check.untyped = nil
 	check.delayed = nil
 	check.finals = nil
+	check.sandboxes = nil
 
 	// determine package name and collect valid files
 	pkg := check.pkg
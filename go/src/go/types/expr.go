@@ -375,6 +375,7 @@ func (check *Checker) updateExprType(x ast.Expr, typ Type, final bool) {
 	switch x := x.(type) {
 	case *ast.BadExpr,
 		*ast.FuncLit,
+		*ast.SandboxLit,
 		*ast.CompositeLit,
 		*ast.IndexExpr,
 		*ast.SliceExpr,
@@ -1058,6 +1059,12 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 			goto Error
 		}
 
+	case *ast.SandboxLit:
+		check.sandboxLit(x, e)
+		if x.mode == invalid {
+			goto Error
+		}
+
 	case *ast.CompositeLit:
 		var typ, base Type
 
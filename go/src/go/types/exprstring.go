@@ -50,6 +50,11 @@ func WriteExpr(buf *bytes.Buffer, x ast.Expr) {
 		WriteExpr(buf, x.Type)
 		buf.WriteString(" literal)") // shortened
 
+	case *ast.SandboxLit:
+		buf.WriteString("(sandbox ")
+		WriteExpr(buf, x.Func.Type)
+		buf.WriteString(" literal)") // shortened
+
 	case *ast.CompositeLit:
 		buf.WriteByte('(')
 		WriteExpr(buf, x.Type)
@@ -0,0 +1,100 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file implements type-checking of sandbox literals.
+
+package types
+
+import (
+	"go/ast"
+	"go/constant"
+)
+
+// A Sandbox describes a type-checked sandbox literal.
+type Sandbox struct {
+	Name string // sandbox name; or "" for unnamed sandboxes
+	Mem  string // memory view
+	Sys  string // syscall classes
+
+	// Captured lists the variables declared by enclosing functions
+	// that are used within the sandbox, in the order they are encountered.
+	Captured []*Var
+}
+
+// sandboxLit type-checks the sandbox literal e. A sandbox literal is a
+// function value whose configuration operands are untyped string constants.
+func (check *Checker) sandboxLit(x *operand, e *ast.SandboxLit) {
+	sb := new(Sandbox)
+	if e.Name != nil {
+		sb.Name = check.sandboxConfig(e.Name, "sandbox name")
+	}
+	sb.Mem = check.sandboxConfig(e.Mem, "sandbox memory view")
+	sb.Sys = check.sandboxConfig(e.Sys, "sandbox syscall classes")
+
+	sig, ok := check.typ(e.Func.Type).(*Signature)
+	if !ok {
+		check.invalidAST(e.Pos(), "invalid sandbox literal %s", e)
+		x.mode = invalid
+		return
+	}
+
+	// Variables resolved within the function scope of the sandbox
+	// but declared outside of it are captured (see sandboxCapture).
+	if check.sandboxes == nil {
+		check.sandboxes = make(map[*Scope]*Sandbox)
+	}
+	check.sandboxes[sig.scope] = sb
+	if m := check.Sandboxes; m != nil {
+		m[e] = sb
+	}
+
+	// Like for function literals, the body is type-checked later
+	// (see the corresponding case in exprInternal).
+	decl := check.decl
+	iota := check.iota
+	check.later(func() {
+		check.funcBody(decl, "<sandbox literal>", sig, e.Func.Body, iota)
+	})
+	check.recordTypeAndValue(e.Func, value, sig, nil)
+	x.mode = value
+	x.typ = sig
+}
+
+// sandboxConfig type-checks a configuration operand of a sandbox literal
+// and returns its value.
+func (check *Checker) sandboxConfig(e ast.Expr, what string) string {
+	var x operand
+	check.expr(&x, e)
+	if x.mode == invalid {
+		return ""
+	}
+	if x.mode != constant_ || !isString(x.typ) || isTyped(x.typ) {
+		check.errorf(x.pos(), "%s must be an untyped string constant, got %s", what, &x)
+		return ""
+	}
+	return constant.StringVal(x.val)
+}
+
+// sandboxCapture records v as captured by every sandbox between the
+// current scope and the scope declaring v.
+func (check *Checker) sandboxCapture(v *Var) {
+	if len(check.sandboxes) == 0 || v.parent == nil || v.parent == Universe || v.parent == check.pkg.scope {
+		return
+	}
+	for s := check.scope; s != nil && s != v.parent; s = s.parent {
+		if sb := check.sandboxes[s]; sb != nil && !sb.captures(v) {
+			sb.Captured = append(sb.Captured, v)
+		}
+	}
+}
+
+// captures reports whether v is already captured by sb.
+func (sb *Sandbox) captures(v *Var) bool {
+	for _, c := range sb.Captured {
+		if c == v {
+			return true
+		}
+	}
+	return false
+}
@@ -91,6 +91,7 @@ func (check *Checker) ident(x *operand, e *ast.Ident, def *Named, wantType bool)
 		if obj.pkg == check.pkg {
 			obj.used = true
 		}
+		check.sandboxCapture(obj)
 		check.addDeclDep(obj)
 		if typ == Typ[Invalid] {
 			return
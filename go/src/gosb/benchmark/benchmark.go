@@ -29,6 +29,7 @@ type Benchmark struct {
 	registerDuration int64 // ns
 	execute          uint64
 	prolog           uint64
+	epilog           uint64
 	growth           uint64
 }
 
@@ -70,6 +71,7 @@ func (b *Benchmark) Reset() {
 	b.register = 0
 	b.execute = 0
 	b.prolog = 0
+	b.epilog = 0
 	b.transferDuration = 0
 	b.registerDuration = 0
 }
@@ -96,6 +98,7 @@ func (b *Benchmark) BenchProlog(id commons.SandId) {
 
 //go:nosplit
 func (b *Benchmark) BenchEpilog(id commons.SandId) {
+	atomic.AddUint64(&b.epilog, 1)
 }
 
 //go:nosplit
@@ -130,6 +133,7 @@ func (b *Benchmark) Dump() {
 	fmt.Println("/// Benchmarks ///")
 	fmt.Printf("Initialization: %dμs\n", b.initDuration.Microseconds())
 	fmt.Printf("prolog: %d ", b.prolog)
+	fmt.Printf("epilog: %d ", b.epilog)
 	fmt.Printf("execute: %d ", b.execute)
 	fmt.Printf("register: %d  (%dμs) ", b.register, toμs(b.registerDuration))
 	fmt.Printf("transfer: %d ", b.transfer)
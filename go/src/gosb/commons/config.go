@@ -17,7 +17,7 @@ import (
 // another package).
 // The grammar is:
 // perm := [R]?[W]?[X]? || P
-// entry := name:rights
+// entry := name:rights // name is a package or a package pattern, see pattern.go
 // config := entry1,entry2,... // separated by commas
 //
 // The second argument represent syscall classes that are whitelisted for this sandbox.
@@ -111,6 +111,11 @@ func parseEntry(entry string) (Entry, error) {
 	if perm == P_VAL && name != SELF_IDENTIFIER {
 		return Entry{}, fmt.Errorf("Pristine applied to non self package")
 	}
+	if IsPackagePattern(name) {
+		if err := checkPattern(name); err != nil {
+			return Entry{}, err
+		}
+	}
 	return Entry{name, perm}, nil
 }
 
@@ -11,7 +11,6 @@ func TestSingleGeneralMemoryView(t *testing.T) {
 	}{
 		{"", 0},
 		{"foo:U", U_VAL},
-		{"foo:P", P_VAL},
 		{"foo:R", R_VAL},
 		{"foo:RX", R_VAL | X_VAL},
 		{"foo:XR", X_VAL | R_VAL},
@@ -22,6 +21,8 @@ func TestSingleGeneralMemoryView(t *testing.T) {
 		{"foo:XWR", R_VAL | W_VAL | X_VAL},
 	}
 	incorrectViews := []string{
+		// Only self can be pristine.
+		"foo:P",
 		":",
 		"foo",
 		"foo:",
@@ -37,7 +38,7 @@ func TestSingleGeneralMemoryView(t *testing.T) {
 	}
 
 	for _, c := range correctViews {
-		res, err := ParseMemoryView(c.s)
+		res, _, err := ParseMemoryView(c.s)
 		if err != nil {
 			t.Errorf(err.Error())
 		}
@@ -53,7 +54,7 @@ func TestSingleGeneralMemoryView(t *testing.T) {
 	}
 
 	for _, c := range incorrectViews {
-		_, err := ParseMemoryView(c)
+		_, _, err := ParseMemoryView(c)
 		if err == nil {
 			t.Errorf("Failed to catch bad entry %v\n", c)
 		}
@@ -78,7 +79,7 @@ func TestMultipleMemoryViews(t *testing.T) {
 	}
 
 	for _, c := range correct {
-		res, err := ParseMemoryView(c.s)
+		res, _, err := ParseMemoryView(c.s)
 		if err != nil {
 			t.Errorf(err.Error())
 		}
@@ -96,7 +97,7 @@ func TestMultipleMemoryViews(t *testing.T) {
 	}
 
 	for _, c := range incorrect {
-		_, err := ParseMemoryView(c)
+		_, _, err := ParseMemoryView(c)
 		if err == nil {
 			t.Errorf("Entry should have triggered error %v\n", c)
 		}
@@ -0,0 +1,480 @@
+package commons
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"unsafe"
+)
+
+// This file defines the encoding of the .bloated, .sandboxes and .gosbsyms
+// sections that the linker adds to binaries and that gosb reads at startup,
+// as well as of the sandbox information that the compiler records in object
+// files for the linker.
+//
+// A section starts with a header:
+// magic "gosb" | version uint16 | kind byte | payload length uint32 | crc32 uint32
+// followed by the payload, a sequence of varints and length-prefixed strings.
+// All fixed-size integers are little endian.
+//
+// Addresses are encoded as an index in a table of bases, the start of the Go
+// sections, and an offset from that base. The table is relocated when the image
+// is loaded at a random address (PIE) or when the host linker moves the Go
+// sections independently (external linking). Sections of size 0 are not
+// relocated.
+
+const (
+	// EncodingVersion must be bumped whenever the payload format changes.
+	EncodingVersion = 4
+
+	encodingMagic      = "gosb"
+	encodingHeaderSize = len(encodingMagic) + 2 + 1 + 4 + 4
+
+	kindPackages = 'P'
+	kindDomains  = 'S'
+	kindSymbols  = 'Y'
+	kindObject   = 'O'
+)
+
+// SectionBases are the symbols marking the start of the Go sections, relative
+// to which addresses are encoded. Must match _GOSB_BASES in runtime/gosb.go.
+var SectionBases = []string{
+	"runtime.text",
+	"runtime.rodata",
+	"runtime.types",
+	"runtime.typelink",
+	"runtime.itablink",
+	"runtime.symtab",
+	"runtime.pclntab",
+	"runtime.noptrdata",
+	"runtime.data",
+	"runtime.bss",
+	"runtime.noptrbss",
+}
+
+// Symbol is an entry of the .gosbsyms section, the subset of the symbol table
+// that gosb needs at startup.
+type Symbol struct {
+	Name string
+	Addr uint64
+	Size uint64
+}
+
+// ObjSandbox is a sandbox as recorded by the compiler in the object file of
+// the package that declares it.
+type ObjSandbox struct {
+	Func     string
+	Id       string
+	Mem      string
+	Sys      string
+	Packages []string
+}
+
+// ObjUnsafe is a construct that lets a package escape the memory view of a
+// sandbox, as recorded by the compiler.
+type ObjUnsafe struct {
+	Kind string // "unsafe", "linkname", "asm" or "cgo"
+	Pos  string // "-" when unknown
+	Sym  string
+}
+
+// EncodeObject encodes the sandboxes and unsafe constructs of a package, that
+// the compiler stores in a symbol of its object file.
+func EncodeObject(sbs []ObjSandbox, uses []ObjUnsafe) []byte {
+	var e encoder
+	e.uvarint(uint64(len(sbs)))
+	for _, sb := range sbs {
+		e.string(sb.Func)
+		e.string(sb.Id)
+		e.string(sb.Mem)
+		e.string(sb.Sys)
+		e.uvarint(uint64(len(sb.Packages)))
+		for _, p := range sb.Packages {
+			e.string(p)
+		}
+	}
+	e.uvarint(uint64(len(uses)))
+	for _, u := range uses {
+		e.string(u.Kind)
+		e.string(u.Pos)
+		e.string(u.Sym)
+	}
+	return e.finish(kindObject)
+}
+
+// DecodeObject decodes the content written by EncodeObject.
+func DecodeObject(b []byte) ([]ObjSandbox, []ObjUnsafe, error) {
+	d, err := newDecoder(b, kindObject, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	sbs := make([]ObjSandbox, d.count())
+	for i := range sbs {
+		sb := &sbs[i]
+		sb.Func = d.string()
+		sb.Id = d.string()
+		sb.Mem = d.string()
+		sb.Sys = d.string()
+		if n := d.count(); n > 0 {
+			sb.Packages = make([]string, n)
+			for j := range sb.Packages {
+				sb.Packages[j] = d.string()
+			}
+		}
+	}
+	var uses []ObjUnsafe
+	if n := d.count(); n > 0 {
+		uses = make([]ObjUnsafe, n)
+		for i := range uses {
+			uses[i].Kind = d.string()
+			uses[i].Pos = d.string()
+			uses[i].Sym = d.string()
+		}
+	}
+	return sbs, uses, d.done()
+}
+
+// EncodePackages encodes the content of the .bloated section: the packages
+// followed by the guard pages between them.
+func EncodePackages(pkgs []*Package, guards []Guard, bases []uint64) []byte {
+	e := encoder{bases: bases}
+	e.uvarint(uint64(len(pkgs)))
+	for _, p := range pkgs {
+		e.string(p.Name)
+		e.varint(int64(p.Id))
+		e.sections(p.Sects)
+		e.sections(p.Dynamic)
+	}
+	e.uvarint(uint64(len(guards)))
+	for _, g := range guards {
+		e.sections([]Section{g.Section})
+		e.string(g.Before)
+		e.string(g.After)
+	}
+	return e.finish(kindPackages)
+}
+
+// DecodePackages decodes the content of the .bloated section.
+func DecodePackages(b []byte, bases []uint64) ([]*Package, []Guard, error) {
+	d, err := newDecoder(b, kindPackages, bases)
+	if err != nil {
+		return nil, nil, err
+	}
+	pkgs := make([]*Package, d.count())
+	for i := range pkgs {
+		p := &Package{}
+		p.Name = d.string()
+		p.Id = int(d.varint())
+		p.Sects = d.sections()
+		p.Dynamic = d.sections()
+		pkgs[i] = p
+	}
+	var guards []Guard
+	if n := d.count(); n > 0 {
+		guards = make([]Guard, n)
+		for i := range guards {
+			if s := d.sections(); len(s) == 1 {
+				guards[i].Section = s[0]
+			} else {
+				d.fail(errors.New("gosb: malformed guard page"))
+			}
+			guards[i].Before = d.string()
+			guards[i].After = d.string()
+		}
+	}
+	return pkgs, guards, d.done()
+}
+
+// EncodeDomains encodes the content of the .sandboxes section.
+func EncodeDomains(domains []*SandboxDomain) []byte {
+	var e encoder
+	e.uvarint(uint64(len(domains)))
+	for _, sb := range domains {
+		e.string(sb.Id)
+		e.string(sb.Func)
+		for _, m := range sb.Sys {
+			e.uvarint(m)
+		}
+		names := make([]string, 0, len(sb.View))
+		for n := range sb.View {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		e.uvarint(uint64(len(names)))
+		for _, n := range names {
+			e.string(n)
+			e.buf = append(e.buf, sb.View[n])
+		}
+		e.uvarint(uint64(len(sb.Pkgs)))
+		for _, p := range sb.Pkgs {
+			e.string(p)
+		}
+		if sb.Pristine {
+			e.buf = append(e.buf, 1)
+		} else {
+			e.buf = append(e.buf, 0)
+		}
+	}
+	return e.finish(kindDomains)
+}
+
+// DecodeDomains decodes the content of the .sandboxes section.
+func DecodeDomains(b []byte) ([]*SandboxDomain, error) {
+	d, err := newDecoder(b, kindDomains, nil)
+	if err != nil {
+		return nil, err
+	}
+	domains := make([]*SandboxDomain, d.count())
+	for i := range domains {
+		sb := &SandboxDomain{}
+		sb.Id = d.string()
+		sb.Func = d.string()
+		for j := range sb.Sys {
+			sb.Sys[j] = d.uvarint()
+		}
+		if n := d.count(); n > 0 {
+			sb.View = make(map[string]uint8, n)
+			for j := 0; j < n; j++ {
+				name := d.string()
+				sb.View[name] = d.byte()
+			}
+		}
+		if n := d.count(); n > 0 {
+			sb.Pkgs = make([]string, n)
+			for j := range sb.Pkgs {
+				sb.Pkgs[j] = d.string()
+			}
+		}
+		sb.Pristine = d.byte() != 0
+		domains[i] = sb
+	}
+	return domains, d.done()
+}
+
+// EncodeSymbols encodes the content of the .gosbsyms section.
+func EncodeSymbols(syms []Symbol, bases []uint64) []byte {
+	e := encoder{bases: bases}
+	e.uvarint(uint64(len(syms)))
+	for _, s := range syms {
+		e.string(s.Name)
+		e.addr(s.Addr)
+		e.uvarint(s.Size)
+	}
+	return e.finish(kindSymbols)
+}
+
+// DecodeSymbols decodes the content of the .gosbsyms section.
+func DecodeSymbols(b []byte, bases []uint64) ([]Symbol, error) {
+	d, err := newDecoder(b, kindSymbols, bases)
+	if err != nil {
+		return nil, err
+	}
+	syms := make([]Symbol, d.count())
+	for i := range syms {
+		syms[i].Name = d.string()
+		syms[i].Addr = d.addr()
+		syms[i].Size = d.uvarint()
+	}
+	return syms, d.done()
+}
+
+// SectionAt returns the encoded section that starts at p in the loaded image.
+// The length is taken from the header; if p does not point to a section
+// header, only the header bytes are returned and decoding reports the error.
+func SectionAt(p unsafe.Pointer) []byte {
+	hdr := (*[encodingHeaderSize]byte)(p)[:]
+	if string(hdr[:4]) != encodingMagic {
+		return hdr
+	}
+	n := encodingHeaderSize + int(binary.LittleEndian.Uint32(hdr[7:]))
+	return (*[1 << 30]byte)(p)[:n:n]
+}
+
+// BaseOf returns the index of the base addr is encoded relative to: the
+// highest base below or equal to addr, the first one on ties.
+func BaseOf(bases []uint64, addr uint64) int {
+	idx := 0
+	for i, b := range bases {
+		if b <= addr && (b > bases[idx] || bases[idx] > addr) {
+			idx = i
+		}
+	}
+	return idx
+}
+
+type encoder struct {
+	buf   []byte
+	bases []uint64
+}
+
+func (e *encoder) uvarint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	e.buf = append(e.buf, tmp[:binary.PutUvarint(tmp[:], v)]...)
+}
+
+func (e *encoder) varint(v int64) {
+	var tmp [binary.MaxVarintLen64]byte
+	e.buf = append(e.buf, tmp[:binary.PutVarint(tmp[:], v)]...)
+}
+
+func (e *encoder) string(s string) {
+	e.uvarint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) addr(a uint64) {
+	i := BaseOf(e.bases, a)
+	e.uvarint(uint64(i))
+	e.varint(int64(a - e.bases[i]))
+}
+
+func (e *encoder) sections(sects []Section) {
+	e.uvarint(uint64(len(sects)))
+	for _, s := range sects {
+		e.uvarint(s.Size)
+		if s.Size == 0 {
+			e.uvarint(s.Addr)
+		} else {
+			e.addr(s.Addr)
+		}
+		e.buf = append(e.buf, s.Prot)
+	}
+}
+
+// finish prepends the header to the payload.
+func (e *encoder) finish(kind byte) []byte {
+	res := make([]byte, encodingHeaderSize, encodingHeaderSize+len(e.buf))
+	copy(res, encodingMagic)
+	binary.LittleEndian.PutUint16(res[4:], EncodingVersion)
+	res[6] = kind
+	binary.LittleEndian.PutUint32(res[7:], uint32(len(e.buf)))
+	binary.LittleEndian.PutUint32(res[11:], crc32.ChecksumIEEE(e.buf))
+	return append(res, e.buf...)
+}
+
+var errTruncated = errors.New("gosb: truncated section")
+
+// decoder reads a payload. The first error is sticky and reported by done.
+type decoder struct {
+	buf   []byte
+	err   error
+	bases []uint64
+}
+
+// newDecoder checks the header of b and returns a decoder for its payload.
+func newDecoder(b []byte, kind byte, bases []uint64) (*decoder, error) {
+	if len(b) < encodingHeaderSize || string(b[:4]) != encodingMagic {
+		return nil, errors.New("gosb: missing section header, binary built by an incompatible linker")
+	}
+	if v := binary.LittleEndian.Uint16(b[4:]); v != EncodingVersion {
+		return nil, fmt.Errorf("gosb: section version %d, want %d: binary built by an incompatible linker", v, EncodingVersion)
+	}
+	if b[6] != kind {
+		return nil, fmt.Errorf("gosb: section of kind %q, want %q", b[6], kind)
+	}
+	payload := b[encodingHeaderSize:]
+	if n := binary.LittleEndian.Uint32(b[7:]); uint64(n) != uint64(len(payload)) {
+		if uint64(n) > uint64(len(payload)) {
+			return nil, errTruncated
+		}
+		payload = payload[:n]
+	}
+	if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(b[11:]) {
+		return nil, errors.New("gosb: section checksum mismatch")
+	}
+	return &decoder{buf: payload, bases: bases}, nil
+}
+
+func (d *decoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+	d.buf = nil
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.fail(errTruncated)
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.fail(errTruncated)
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+// count reads the length of a sequence, bounded by the remaining payload so
+// that a corrupted length cannot trigger a huge allocation.
+func (d *decoder) count() int {
+	n := d.uvarint()
+	if n > uint64(len(d.buf)) {
+		d.fail(errTruncated)
+		return 0
+	}
+	return int(n)
+}
+
+// addr reads an address encoded relative to one of the bases.
+func (d *decoder) addr() uint64 {
+	i := d.uvarint()
+	off := d.varint()
+	if i >= uint64(len(d.bases)) {
+		d.fail(fmt.Errorf("gosb: address relative to base %d, only %d bases", i, len(d.bases)))
+		return 0
+	}
+	return d.bases[i] + uint64(off)
+}
+
+func (d *decoder) byte() byte {
+	if len(d.buf) == 0 {
+		d.fail(errTruncated)
+		return 0
+	}
+	b := d.buf[0]
+	d.buf = d.buf[1:]
+	return b
+}
+
+func (d *decoder) string() string {
+	n := d.count()
+	s := string(d.buf[:n])
+	d.buf = d.buf[n:]
+	return s
+}
+
+func (d *decoder) sections() []Section {
+	n := d.count()
+	if n == 0 {
+		return nil
+	}
+	sects := make([]Section, n)
+	for i := range sects {
+		sects[i].Size = d.uvarint()
+		if sects[i].Size == 0 {
+			sects[i].Addr = d.uvarint()
+		} else {
+			sects[i].Addr = d.addr()
+		}
+		sects[i].Prot = d.byte()
+	}
+	return sects
+}
+
+// done reports the first decoding error, or trailing bytes in the payload.
+func (d *decoder) done() error {
+	if d.err == nil && len(d.buf) != 0 {
+		d.err = errors.New("gosb: trailing bytes in section")
+	}
+	return d.err
+}
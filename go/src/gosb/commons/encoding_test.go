@@ -0,0 +1,131 @@
+package commons
+
+import (
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestEncodingRoundTrip(t *testing.T) {
+	pkgs := []*Package{
+		{"main", 12, []Section{{0x401000, 0x2000, R_VAL | X_VAL}, {0, 0, R_VAL}}, nil},
+		{TrustedPkgName, -1, []Section{{0x400000, 0x1000, R_VAL}}, []Section{{0xc000000000, 0x4000, R_VAL | W_VAL}}},
+	}
+	bases := []uint64{0x401000, 0x480000}
+	guards := []Guard{{Section{0x403000, 0x1000, 0}, "main", TrustedPkgName}}
+	got, gotg, err := DecodePackages(EncodePackages(pkgs, guards, bases), bases)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, pkgs) {
+		t.Errorf("got packages %v, want %v", got, pkgs)
+	}
+	if !reflect.DeepEqual(gotg, guards) {
+		t.Errorf("got guards %v, want %v", gotg, guards)
+	}
+
+	domains := []*SandboxDomain{
+		{"main:0", "main.main.func1", SyscallAll, map[string]uint8{"main": R_VAL, "bytes": R_VAL | W_VAL}, []string{"main", "bytes"}, true},
+		{"-1", "-1", SyscallMask{}, nil, []string{TrustedPkgName}, false},
+	}
+	gotd, err := DecodeDomains(EncodeDomains(domains))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotd, domains) {
+		t.Errorf("got domains %v, want %v", gotd, domains)
+	}
+
+	syms := []Symbol{{"main.main.func1", 0x401200, 0x80}, {"runtime.pclntab", 0x480000, 0x1f000}}
+	gots, err := DecodeSymbols(EncodeSymbols(syms, bases), bases)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gots, syms) {
+		t.Errorf("got symbols %v, want %v", gots, syms)
+	}
+}
+
+func TestEncodingObject(t *testing.T) {
+	sbs := []ObjSandbox{
+		{"main.main.func1", "main:0", "bytes:RW", "file,net", []string{"bytes", "fmt"}},
+		{"main.f", "\"f\"", "", "", nil},
+	}
+	uses := []ObjUnsafe{{"unsafe", "main.go:3", "unsafe"}, {"asm", "-", "main.g"}}
+	gots, gotu, err := DecodeObject(EncodeObject(sbs, uses))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gots, sbs) {
+		t.Errorf("got sandboxes %v, want %v", gots, sbs)
+	}
+	if !reflect.DeepEqual(gotu, uses) {
+		t.Errorf("got unsafe uses %v, want %v", gotu, uses)
+	}
+}
+
+func TestEncodingRelocation(t *testing.T) {
+	// The text and data sections are moved independently, as the host
+	// linker may do.
+	linked := []uint64{0x401000, 0x4a0000}
+	loaded := []uint64{0x7f3a12402000, 0x7f3a12600000}
+	pkgs := []*Package{{"main", 1, []Section{{0x402000, 0x1000, R_VAL | X_VAL}, {0x4a1000, 0x1000, R_VAL | W_VAL}, {0, 0, R_VAL}}, nil}}
+	got, _, err := DecodePackages(EncodePackages(pkgs, nil, linked), loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Section{{0x7f3a12403000, 0x1000, R_VAL | X_VAL}, {0x7f3a12601000, 0x1000, R_VAL | W_VAL}, {0, 0, R_VAL}}
+	if !reflect.DeepEqual(got[0].Sects, want) {
+		t.Errorf("got sections %v, want %v", got[0].Sects, want)
+	}
+	syms, err := DecodeSymbols(EncodeSymbols([]Symbol{{"runtime.pclntab", 0x4a0800, 0x100}}, linked), loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if syms[0].Addr != 0x7f3a12600800 {
+		t.Errorf("got symbol at %#x, want %#x", syms[0].Addr, 0x7f3a12600800)
+	}
+	if _, err := DecodeSymbols(EncodeSymbols([]Symbol{{"runtime.pclntab", 0x4a0800, 0x100}}, linked), loaded[:1]); err == nil {
+		t.Errorf("decoding with missing bases succeeded")
+	}
+}
+
+func TestSectionAt(t *testing.T) {
+	enc := EncodeSymbols([]Symbol{{"main.main.func1", 0x401200, 0x80}}, []uint64{0})
+	// Simulate the section being followed by other data in the image.
+	image := append(append([]byte(nil), enc...), "trailing"...)
+	if got := SectionAt(unsafe.Pointer(&image[0])); string(got) != string(enc) {
+		t.Errorf("SectionAt returned %d bytes, want %d", len(got), len(enc))
+	}
+	image = make([]byte, encodingHeaderSize)
+	if _, err := DecodeSymbols(SectionAt(unsafe.Pointer(&image[0])), nil); err == nil {
+		t.Errorf("SectionAt on garbage decoded without error")
+	}
+}
+
+func TestEncodingErrors(t *testing.T) {
+	bases := []uint64{0}
+	good := EncodePackages([]*Package{{"main", 1, []Section{{0x1000, 0x1000, R_VAL}}, nil}}, nil, bases)
+	corrupt := func(f func(b []byte) []byte) []byte {
+		return f(append([]byte(nil), good...))
+	}
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"json", []byte(`[{"Name":"main"}]`), "missing section header"},
+		{"version", corrupt(func(b []byte) []byte { binary.LittleEndian.PutUint16(b[4:], EncodingVersion+1); return b }), "section version"},
+		{"kind", EncodeDomains(nil), "section of kind"},
+		{"truncated", good[:len(good)-1], "truncated"},
+		{"checksum", corrupt(func(b []byte) []byte { b[len(b)-1] ^= 1; return b }), "checksum"},
+	}
+	for _, tt := range tests {
+		_, _, err := DecodePackages(tt.data, bases)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
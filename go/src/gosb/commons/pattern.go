@@ -0,0 +1,163 @@
+package commons
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The names of the memory view entries can be package patterns:
+// ... matches any string, including slashes, as in the package patterns of
+// the go command, * matches any string without slashes and ? matches any
+// character but a slash. As a special case, x/... also matches x, unless x
+// contains ... itself: .../... only matches paths with several elements.
+// For instance, golang.org/x/net/... covers golang.org/x/net and all of its
+// subpackages, while golang.org/x/net/* only covers its direct subpackages.
+
+const (
+	PATTERN_SUBTREE = "..."
+	PATTERN_GLOBS   = "*?"
+)
+
+// IsPackagePattern reports whether name contains wildcards.
+func IsPackagePattern(name string) bool {
+	return strings.Contains(name, PATTERN_SUBTREE) || strings.ContainsAny(name, PATTERN_GLOBS)
+}
+
+// MatchPackage reports whether the import path pkg matches pattern.
+func MatchPackage(pattern, pkg string) bool {
+	if root := strings.TrimSuffix(pattern, "/"+PATTERN_SUBTREE); root != pattern && !strings.Contains(root, PATTERN_SUBTREE) && matchPattern(root, pkg) {
+		return true
+	}
+	return matchPattern(pattern, pkg)
+}
+
+func matchPattern(pattern, name string) bool {
+	for len(pattern) > 0 {
+		switch {
+		case strings.HasPrefix(pattern, PATTERN_SUBTREE):
+			for i := 0; i <= len(name); i++ {
+				if matchPattern(pattern[len(PATTERN_SUBTREE):], name[i:]) {
+					return true
+				}
+			}
+			return false
+		case pattern[0] == '*':
+			for i := 0; i <= len(name); i++ {
+				if matchPattern(pattern[1:], name[i:]) {
+					return true
+				}
+				if i < len(name) && name[i] == '/' {
+					break
+				}
+			}
+			return false
+		case pattern[0] == '?':
+			if len(name) == 0 || name[0] == '/' {
+				return false
+			}
+		default:
+			if len(name) == 0 || name[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// CanImport reports whether the package importer is allowed to import path,
+// following the rules for internal packages. std tells whether importer is
+// part of the standard library, the only one to import internal/....
+// Patterns of memory views are expanded to the packages that pass this check.
+func CanImport(importer, path string, std bool) bool {
+	var i int
+	switch {
+	case strings.HasSuffix(path, "/internal"):
+		i = len(path) - len("internal")
+	case strings.Contains(path, "/internal/"):
+		i = strings.LastIndex(path, "/internal/") + 1
+	case path == "internal", strings.HasPrefix(path, "internal/"):
+		i = 0
+	default:
+		return true
+	}
+	parent := strings.TrimSuffix(path[:i], "/")
+	if parent == "" {
+		return std
+	}
+	return importer == parent || strings.HasPrefix(importer, parent+"/")
+}
+
+// checkPattern validates the package pattern of a memory view entry.
+func checkPattern(pattern string) error {
+	if strings.HasPrefix(pattern, "/") || strings.HasSuffix(pattern, "/") || strings.Contains(pattern, "//") {
+		return fmt.Errorf("invalid package pattern %v", pattern)
+	}
+	return nil
+}
+
+// literals is the number of characters of pattern that are not wildcards.
+func literals(pattern string) int {
+	n := len(pattern) - len(PATTERN_SUBTREE)*strings.Count(pattern, PATTERN_SUBTREE)
+	for _, c := range PATTERN_GLOBS {
+		n -= strings.Count(pattern, string(c))
+	}
+	return n
+}
+
+// moreSpecific reports whether a takes precedence over b when both match a
+// package: exact names come first, then the patterns with more literals.
+func moreSpecific(a, b string) bool {
+	aexact, bexact := !IsPackagePattern(a), !IsPackagePattern(b)
+	if aexact != bexact {
+		return aexact
+	}
+	return literals(a) > literals(b)
+}
+
+// ExpandMemoryView resolves the patterns of the memory view against the
+// package set pkgs. The result holds the exact entries of view, along with
+// the packages of pkgs that its patterns match. An exact entry takes
+// precedence over the patterns that match the same package. Otherwise, the
+// most specific pattern applies, see moreSpecific, and equally specific
+// patterns give the intersection of their rights.
+func ExpandMemoryView(view map[string]uint8, pkgs []string) map[string]uint8 {
+	res := make(map[string]uint8)
+	var patterns []string
+	for name, perm := range view {
+		if IsPackagePattern(name) {
+			patterns = append(patterns, name)
+			continue
+		}
+		res[name] = perm
+	}
+	if len(patterns) == 0 {
+		return res
+	}
+	for _, pkg := range pkgs {
+		if _, ok := res[pkg]; ok {
+			continue
+		}
+		var best []string
+		for _, p := range patterns {
+			if !MatchPackage(p, pkg) {
+				continue
+			}
+			switch {
+			case len(best) == 0 || moreSpecific(p, best[0]):
+				best = []string{p}
+			case !moreSpecific(best[0], p):
+				best = append(best, p)
+			}
+		}
+		if len(best) == 0 {
+			continue
+		}
+		perm := ^uint8(0)
+		for _, p := range best {
+			perm &= view[p]
+		}
+		res[pkg] = perm
+	}
+	return res
+}
@@ -0,0 +1,111 @@
+package commons
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchPackage(t *testing.T) {
+	tests := []struct {
+		pattern, pkg string
+		want         bool
+	}{
+		{"net", "net", true},
+		{"net", "net/http", false},
+		{"net/...", "net", true},
+		{"net/...", "net/http", true},
+		{"net/...", "net/http/pprof", true},
+		{"net/...", "netx", false},
+		{"net...", "netx", true},
+		{"...", "main", true},
+		{"golang.org/x/.../internal", "golang.org/x/net/http2/internal", true},
+		{"golang.org/x/.../internal", "golang.org/x/net/internal/socket", false},
+		{"net/*", "net/http", true},
+		{"net/*", "net/http/pprof", false},
+		{"net/*", "net", false},
+		{"net/*/pprof", "net/http/pprof", true},
+		{"encoding/*32", "encoding/base32", true},
+		{"encoding/*32", "encoding/base64", false},
+		{"encoding/base?4", "encoding/base64", true},
+		{"encoding/base?4", "encoding/base/4", false},
+		{".../...", "a", false},
+		{".../...", "a/b", true},
+		{"net/*/...", "net/http", true},
+		{"net/*/...", "net", false},
+	}
+	for _, tt := range tests {
+		if got := MatchPackage(tt.pattern, tt.pkg); got != tt.want {
+			t.Errorf("MatchPackage(%q, %q) = %v, want %v", tt.pattern, tt.pkg, got, tt.want)
+		}
+	}
+}
+
+func TestParseMemoryViewPatterns(t *testing.T) {
+	entries, _, err := ParseMemoryView("golang.org/x/net/...:R,encoding/*:RW")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Entry{{"golang.org/x/net/...", R_VAL}, {"encoding/*", R_VAL | W_VAL}}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("got %v, want %v", entries, want)
+	}
+	for _, view := range []string{"/net/...:R", "net//*:R", "net/*/:R"} {
+		if _, _, err := ParseMemoryView(view); err == nil {
+			t.Errorf("ParseMemoryView(%q) succeeded", view)
+		}
+	}
+}
+
+func TestExpandMemoryView(t *testing.T) {
+	view := map[string]uint8{
+		"golang.org/x/net/...":    R_VAL,
+		"golang.org/x/net/http2":  R_VAL | W_VAL,
+		"golang.org/x/net/http/*": R_VAL | X_VAL,
+		"golang.org/x/.../httpg*": R_VAL | W_VAL,
+		"golang.org/x/*/httpg??":  R_VAL | X_VAL,
+		"fmt":                     U_VAL,
+	}
+	pkgs := []string{
+		"golang.org/x/net",
+		"golang.org/x/net/http2",
+		"golang.org/x/net/http/httpguts",
+		"golang.org/x/net/httpgax",
+		"golang.org/x/text",
+		"main",
+	}
+	want := map[string]uint8{
+		"fmt":                    U_VAL,
+		"golang.org/x/net":       R_VAL,
+		"golang.org/x/net/http2": R_VAL | W_VAL,
+		// Longer than golang.org/x/net/....
+		"golang.org/x/net/http/httpguts": R_VAL | X_VAL,
+		// Both httpg patterns have 19 literals.
+		"golang.org/x/net/httpgax": R_VAL,
+	}
+	if got := ExpandMemoryView(view, pkgs); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCanImport(t *testing.T) {
+	tests := []struct {
+		importer, path string
+		std            bool
+		want           bool
+	}{
+		{"main", "fmt", false, true},
+		{"x", "x/internal", false, true},
+		{"x/y", "x/internal/z", false, true},
+		{"a", "x/internal/z", false, false},
+		{"xy", "x/internal", false, false},
+		{"x/y/internal/z", "x/y/internal/z/internal", false, true},
+		{"x/y", "x/y/internal/z/internal", false, false},
+		{"main", "internal/cpu", false, false},
+		{"fmt", "internal/fmtsort", true, true},
+	}
+	for _, tt := range tests {
+		if got := CanImport(tt.importer, tt.path, tt.std); got != tt.want {
+			t.Errorf("CanImport(%q, %q, %v) = %v, want %v", tt.importer, tt.path, tt.std, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package commons
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A policy bounds the privileges that the sandboxes of a binary may request.
+// It is read from the PolicyFile at the root of the main module and enforced
+// by the linker. Each line is a rule:
+//
+// mem pattern perm    // sandboxes get at most perm on the packages matching pattern
+// sys pattern classes // sandboxes declared in the packages matching pattern
+//                     // allow at most the comma-separated syscall classes
+//
+// perm follows the grammar of the memory views, i.e., [R]?[W]?[X]? or U.
+// classes follows the one of the syscalls, the default class alone allowing
+// no other class.
+// Packages that keep their default rights in a sandbox have all of them.
+// Patterns are the ones of the memory views, see MatchPackage. When several
+// rules of a kind match a package, the most specific one applies, as in
+// ExpandMemoryView, and equally specific rules all apply. Packages that no
+// rule matches are not bounded.
+// Empty lines and lines starting with # are ignored.
+
+const PolicyFile = "gosb.policy"
+
+type PolicyRule struct {
+	Pattern string
+	Perm    uint8       // mem rules
+	Sys     SyscallMask // sys rules
+	Line    int
+}
+
+type Policy struct {
+	Mem []PolicyRule
+	Sys []PolicyRule
+}
+
+func ParsePolicy(data []byte) (*Policy, error) {
+	p := &Policy{}
+	seen := make(map[string]int)
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		f := strings.Fields(text)
+		if len(f) != 3 {
+			return nil, fmt.Errorf("line %d: expected kind, pattern and ceiling, got %q", line, text)
+		}
+		kind, pattern, ceiling := f[0], f[1], f[2]
+		key := kind + " " + pattern
+		if prev, ok := seen[key]; ok {
+			return nil, fmt.Errorf("line %d: duplicated rule for %v, previous rule at line %d", line, key, prev)
+		}
+		seen[key] = line
+		rule := PolicyRule{Pattern: pattern, Line: line}
+		switch kind {
+		case "mem":
+			perm, err := parsePerm(ceiling)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			if perm == P_VAL {
+				return nil, fmt.Errorf("line %d: pristine is not a ceiling", line)
+			}
+			rule.Perm = perm
+			p.Mem = append(p.Mem, rule)
+		case "sys":
+			mask, err := ParseSyscalls(ceiling)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			rule.Sys = mask
+			p.Sys = append(p.Sys, rule)
+		default:
+			return nil, fmt.Errorf("line %d: unknown rule kind %q", line, kind)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// matchRules returns the rules that apply to pkg, i.e., the most specific ones.
+// Several rules apply when their patterns are equally specific.
+func matchRules(rules []PolicyRule, pkg string) []*PolicyRule {
+	var res []*PolicyRule
+	for i := range rules {
+		r := &rules[i]
+		if !MatchPackage(r.Pattern, pkg) {
+			continue
+		}
+		switch {
+		case len(res) == 0 || moreSpecific(r.Pattern, res[0].Pattern):
+			res = []*PolicyRule{r}
+		case !moreSpecific(res[0].Pattern, r.Pattern):
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// Check returns the privileges of the sandbox d, declared in package pkg,
+// that exceed the policy.
+func (p *Policy) Check(d *SandboxDomain, pkg string) []string {
+	var errs []string
+	for _, name := range d.Pkgs {
+		perm := D_VAL
+		if v, ok := d.View[name]; ok {
+			perm = v
+		}
+		for _, r := range matchRules(p.Mem, name) {
+			if extra := perm & DEF_VAL &^ r.Perm; extra != 0 {
+				errs = append(errs, fmt.Sprintf("%s rights on %s exceed %s allowed by %s:%d",
+					permString(perm), name, permString(r.Perm), PolicyFile, r.Line))
+			}
+		}
+	}
+	for _, r := range matchRules(p.Sys, pkg) {
+		var extra SyscallMask
+		for i := range d.Sys {
+			extra[i] = d.Sys[i] &^ r.Sys[i]
+		}
+		if extra == syscallNone {
+			continue
+		}
+		names := []string{"all"}
+		if d.Sys != SyscallAll {
+			var nbs []int
+			names, nbs = DecodeSyscalls(extra)
+			for _, n := range nbs {
+				names = append(names, fmt.Sprintf("#%d", n))
+			}
+		}
+		errs = append(errs, fmt.Sprintf("syscalls %s not allowed by %s:%d",
+			strings.Join(names, ","), PolicyFile, r.Line))
+	}
+	return errs
+}
+
+func permString(perm uint8) string {
+	if perm&DEF_VAL == 0 {
+		return UNMAP
+	}
+	var s string
+	if perm&R_VAL != 0 {
+		s += READ
+	}
+	if perm&W_VAL != 0 {
+		s += WRITE
+	}
+	if perm&X_VAL != 0 {
+		s += EXECUTE
+	}
+	return s
+}
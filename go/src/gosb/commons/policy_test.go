@@ -0,0 +1,71 @@
+package commons
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testPolicy = `
+# Sandboxes can only read the main module,
+# except for its data package.
+mem example.com/m/... R
+mem example.com/m/data RW
+mem ... RWX
+
+sys example.com/m/plugins/... net
+sys ... default
+`
+
+func TestParsePolicyErrors(t *testing.T) {
+	for _, policy := range []string{
+		"mem net",
+		"mem net RWXR",
+		"mem net P",
+		"sys net unknown",
+		"cpu net R",
+		"mem net R\nmem net RW",
+	} {
+		if _, err := ParsePolicy([]byte(policy)); err == nil {
+			t.Errorf("ParsePolicy(%q) succeeded", policy)
+		}
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	p, err := ParsePolicy([]byte(testPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	net, _ := ParseSyscalls("net")
+	file, _ := ParseSyscalls("file")
+	d := &SandboxDomain{
+		Pkgs: []string{"example.com/m", "example.com/m/data", "example.com/m/store", "fmt"},
+		View: map[string]uint8{"example.com/m": R_VAL, "example.com/m/data": R_VAL | W_VAL, "example.com/m/store": R_VAL},
+		Sys:  net,
+	}
+	if errs := p.Check(d, "example.com/m/plugins/a"); errs != nil {
+		t.Errorf("unexpected violations %v", errs)
+	}
+
+	d.View["example.com/m/data"] = R_VAL | W_VAL | X_VAL
+	delete(d.View, "example.com/m/store")
+	d.Sys = file
+	want := []string{
+		"RWX rights on example.com/m/data exceed RW allowed by gosb.policy:5",
+		"RWX rights on example.com/m/store exceed R allowed by gosb.policy:4",
+		"syscalls file,io not allowed by gosb.policy:8",
+	}
+	if errs := p.Check(d, "example.com/m/plugins/a"); !reflect.DeepEqual(errs, want) {
+		t.Errorf("got violations %q, want %q", errs, want)
+	}
+
+	d.Sys = net
+	want = []string{
+		"RWX rights on example.com/m/data exceed RW allowed by gosb.policy:5",
+		"RWX rights on example.com/m/store exceed R allowed by gosb.policy:4",
+		"syscalls net not allowed by gosb.policy:9",
+	}
+	if errs := p.Check(d, "main"); !reflect.DeepEqual(errs, want) {
+		t.Errorf("got violations %q, want %q", errs, want)
+	}
+}
@@ -1,6 +1,8 @@
 package commons
 
 import (
+	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
@@ -88,13 +90,20 @@ func ParseSyscalls(config string) (SyscallMask, error) {
 	}
 	var mask SyscallMask
 	entries := strings.Split(conf, DELIMITER_SYSCLASS)
-	// Add the default entries
-	entries = append(entries, "default")
+	uniq := make(map[string]bool)
 	for _, v := range entries {
+		if _, ok := uniq[v]; ok {
+			return mask, fmt.Errorf("duplicated syscall class %v", v)
+		}
+		uniq[v] = true
 		e, ok := SyscallConfigs[v]
-		Check(ok)
+		if !ok {
+			return mask, fmt.Errorf("unknown syscall class %q", v)
+		}
 		Add(&mask, e)
 	}
+	// Add the default entries
+	Add(&mask, SyscallConfigs["default"])
 	return mask, nil
 }
 
@@ -104,3 +113,37 @@ func Add(m *SyscallMask, other SyscallMask) {
 		m[i] |= other[i]
 	}
 }
+
+// DecodeSyscalls describes mask as the syscall classes it contains, sorted by
+// name, along with the syscalls that do not belong to any of these classes.
+// The mask of a sandbox without syscall restrictions decodes to "all".
+func DecodeSyscalls(mask SyscallMask) ([]string, []int) {
+	if mask == SyscallAll {
+		return []string{"all"}, nil
+	}
+	var classes []string
+	var covered SyscallMask
+	for k, c := range SyscallConfigs {
+		contained := true
+		for i := range c {
+			if c[i]&mask[i] != c[i] {
+				contained = false
+				break
+			}
+		}
+		if contained {
+			classes = append(classes, k)
+			Add(&covered, c)
+		}
+	}
+	sort.Strings(classes)
+	var extras []int
+	for i := range mask {
+		for j := 0; j < 64; j++ {
+			if mask[i]&^covered[i]&(1<<uint(j)) != 0 {
+				extras = append(extras, i*64+j)
+			}
+		}
+	}
+	return classes, extras
+}
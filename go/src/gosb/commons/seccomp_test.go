@@ -0,0 +1,25 @@
+package commons
+
+import (
+	"reflect"
+	"syscall"
+	"testing"
+)
+
+func TestDecodeSyscalls(t *testing.T) {
+	mask, err := ParseSyscalls("net,io")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mask[syscall.SYS_GETPID/64] |= 1 << (syscall.SYS_GETPID % 64)
+	classes, extras := DecodeSyscalls(mask)
+	if want := []string{"default", "io", "net"}; !reflect.DeepEqual(classes, want) {
+		t.Errorf("got classes %v, want %v", classes, want)
+	}
+	if want := []int{syscall.SYS_GETPID}; !reflect.DeepEqual(extras, want) {
+		t.Errorf("got extras %v, want %v", extras, want)
+	}
+	if classes, extras := DecodeSyscalls(SyscallAll); len(classes) != 1 || classes[0] != "all" || extras != nil {
+		t.Errorf("got %v %v for all syscalls, want [all]", classes, extras)
+	}
+}
@@ -1,5 +1,7 @@
 package commons
 
+import "strings"
+
 const (
 	// reservedMemory is a chunk of physical memory reserved starting at
 	// physical address zero. There are some special pages in this region,
@@ -8,8 +10,10 @@ const (
 )
 
 const (
-	TrustedPkgName = "non-bloat"
-	StmpPkgName    = "shared-stmp"
+	TrustedPkgName      = "non-bloat"
+	StmpPkgName         = "shared-stmp"
+	SharedRodataPkgName = "shared-rodata"
+	BackendPkgName      = "gosb"
 )
 
 var (
@@ -28,3 +32,9 @@ var (
 		"gosb",
 	}
 )
+
+// IsBackendPackage reports whether pkg is gosb or one of its subpackages,
+// i.e., part of the backend that enforces the sandboxes.
+func IsBackendPackage(pkg string) bool {
+	return pkg == BackendPkgName || strings.HasPrefix(pkg, BackendPkgName+"/")
+}
@@ -24,6 +24,14 @@ type Section struct {
 	Prot uint8
 }
 
+// Guard is an unmapped page range the linker inserts between the sections of
+// two neighbouring packages.
+type Guard struct {
+	Section
+	Before string
+	After  string
+}
+
 type SandboxMemory struct {
 	Static  *VMAreas
 	Config  *SandboxDomain
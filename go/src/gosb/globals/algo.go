@@ -95,6 +95,30 @@ func compatible(pa, pb int, ga, gb []c.SandId, prots map[int]map[c.SandId]uint8)
 // ComputeMemoryView for dynamic sandboxes computes the view based on the available
 // information.
 func ComputeMemoryView(sbdeps []string, deps map[string][]string, view map[string]uint8) map[string]uint8 {
+	// Resolve the package patterns of the view against the known packages,
+	// except the runtime and its dependencies, and the backend.
+	skip := make(map[string]bool)
+	for todo := []string{"runtime"}; len(todo) > 0; todo = todo[1:] {
+		if !skip[todo[0]] {
+			skip[todo[0]] = true
+			todo = append(todo, deps[todo[0]]...)
+		}
+	}
+	var known []string
+	add := func(pkgs ...string) {
+		for _, p := range pkgs {
+			if !skip[p] && !c.IsBackendPackage(p) {
+				known = append(known, p)
+			}
+		}
+	}
+	add(sbdeps...)
+	for k, v := range deps {
+		add(k)
+		add(v...)
+	}
+	view = c.ExpandMemoryView(view, known)
+
 	result := make(map[string]uint8)
 	toadd := make(map[string]bool)
 	// Init will all the initial packages, including the views.
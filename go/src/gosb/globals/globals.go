@@ -7,7 +7,6 @@ package globals
 * We have to isolate them to allow multi-package access to them.
  */
 import (
-	"debug/elf"
 	"errors"
 	"fmt"
 	c "gosb/commons"
@@ -27,8 +26,8 @@ var (
 	// For debugging for the moment
 	IsDynamic bool = false
 	// Symbols
-	Symbols   []elf.Symbol
-	NameToSym map[string]*elf.Symbol
+	Symbols   []c.Symbol
+	NameToSym map[string]*c.Symbol
 
 	// Packages
 	AllPackages     []*c.Package
@@ -59,6 +58,10 @@ var (
 
 	// Dependencies
 	PkgDeps map[int][]c.SandId
+
+	// Guard pages inserted by the linker and their violation reports.
+	Guards       []c.Guard
+	GuardReports []string
 )
 
 type IdFunc func() c.SandId
@@ -97,3 +100,26 @@ func DynFindId(name string) (int, error) {
 	}
 	return -1, errors.New(fmt.Sprintf("Unable to find an id for %s", name))
 }
+
+// InitGuards registers the guard pages and precomputes their reports, as
+// GuardViolation is called from the fault handlers and cannot allocate.
+func InitGuards(guards []c.Guard) {
+	Guards = guards
+	GuardReports = make([]string, len(guards))
+	for i, g := range guards {
+		GuardReports[i] = fmt.Sprintf("gosb: guard page violation at [%#x, %#x) between packages %s and %s",
+			g.Addr, g.Addr+g.Size, g.Before, g.After)
+	}
+}
+
+// GuardViolation returns the report for an access to addr if it falls in a
+// guard page, and "" otherwise.
+//go:nosplit
+func GuardViolation(addr uint64) string {
+	for i := range Guards {
+		if Guards[i].Addr <= addr && addr < Guards[i].Addr+Guards[i].Size {
+			return GuardReports[i]
+		}
+	}
+	return ""
+}
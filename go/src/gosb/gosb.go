@@ -1,20 +1,18 @@
 package gosb
 
 import (
-	"debug/elf"
-	"encoding/json"
 	"fmt"
 	"gosb/backend"
 	"gosb/commons"
 	"gosb/globals"
 	"gosb/vtx"
 	"gosb/vtx/platform/kvm"
-	"os"
 	"runtime"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 )
 
 var (
@@ -26,6 +24,7 @@ var (
 func Initialize(b backend.Backend) {
 	once.Do(func() {
 		loadPackages()
+		loadGuards()
 		loadSandboxes()
 		updateTrusted()
 		globals.AggregatePackages()
@@ -87,21 +86,24 @@ func getPkgName(name string) string {
 }
 
 func loadPackages() {
-	// Load information from the elf.
-	file, err := elf.Open(os.Args[0])
-	commons.CheckE(err)
-	defer func() { commons.CheckE(file.Close()) }()
-	section := file.Section(".bloated")
-	if section == nil {
+	// Load information from the loaded image. Addresses are relative to
+	// the load addresses of the Go sections.
+	rbases, bloated, _, syms := runtime.GosbSections()
+	if bloated == nil {
 		// No bloat section
 		return
 	}
-	data, err := section.Data()
-	commons.CheckE(err)
+	bases := make([]uint64, len(rbases))
+	for i, b := range rbases {
+		bases[i] = uint64(b)
+	}
 
 	// Initialize globals.
-	globals.AllPackages = make([]*commons.Package, 0)
-	err = json.Unmarshal(data, &globals.AllPackages)
+	var err error
+	var guards []commons.Guard
+	globals.AllPackages, guards, err = commons.DecodePackages(commons.SectionAt(bloated), bases)
+	commons.CheckE(err)
+	globals.InitGuards(guards)
 
 	// Generate maps for packages.
 	globals.NameToPkg = make(map[string]*commons.Package)
@@ -165,23 +167,23 @@ func loadPackages() {
 	}
 
 	// Initialize the symbols.
-	globals.Symbols, err = file.Symbols()
+	globals.Symbols, err = commons.DecodeSymbols(commons.SectionAt(syms), bases)
 	commons.CheckE(err)
 	sort.Slice(globals.Symbols, func(i, j int) bool {
-		return globals.Symbols[i].Value < globals.Symbols[j].Value
+		return globals.Symbols[i].Addr < globals.Symbols[j].Addr
 	})
-	globals.NameToSym = make(map[string]*elf.Symbol)
+	globals.NameToSym = make(map[string]*commons.Symbol)
 	for i, s := range globals.Symbols {
 		globals.NameToSym[s.Name] = &globals.Symbols[i]
 		if s.Name == "runtime.pclntab" {
 			runtimePkg := globals.NameToPkg["runtime"]
 			runtimePkg.Sects = append(runtimePkg.Sects, commons.Section{
-				commons.Round(s.Value, false),
+				commons.Round(s.Addr, false),
 				commons.Round(s.Size, true),
 				commons.R_VAL | commons.USER_VAL,
 			})
 			globals.CommonVMAs.Map(commons.SectVMA(&commons.Section{
-				commons.Round(s.Value, false),
+				commons.Round(s.Addr, false),
 				commons.Round(s.Size, true),
 				commons.R_VAL | commons.USER_VAL,
 			}))
@@ -189,12 +191,28 @@ func loadPackages() {
 	}
 }
 
+// loadGuards revokes all access to the guard pages and lets the runtime
+// report faults on them as sandbox violations.
+func loadGuards() {
+	for _, g := range globals.Guards {
+		_, _, errno := syscall.RawSyscall(syscall.SYS_MPROTECT, uintptr(g.Addr), uintptr(g.Size), syscall.PROT_NONE)
+		if errno != 0 {
+			panic("gosb: unable to protect guard page: " + errno.Error())
+		}
+	}
+	if len(globals.Guards) > 0 {
+		runtime.RegisterGuardFault(guardFault)
+	}
+}
+
+//go:nosplit
+func guardFault(addr uintptr) string {
+	return globals.GuardViolation(uint64(addr))
+}
+
 func loadSandboxes() {
-	file, err := elf.Open(os.Args[0])
-	commons.CheckE(err)
-	defer func() { commons.CheckE(file.Close()) }()
-	section := file.Section(".sandboxes")
-	if section == nil {
+	_, _, sandboxes, _ := runtime.GosbSections()
+	if sandboxes == nil {
 		// No sboxes
 		return
 	}
@@ -204,9 +222,8 @@ func loadSandboxes() {
 	globals.Sandboxes = make(map[commons.SandId]*commons.SandboxMemory)
 	globals.IsPristine = make(map[commons.SandId]bool)
 
-	data, err := section.Data()
-	commons.CheckE(err)
-	err = json.Unmarshal(data, &globals.Configurations)
+	var err error
+	globals.Configurations, err = commons.DecodeDomains(commons.SectionAt(sandboxes))
 	commons.CheckE(err)
 
 	// Use the configurations to create fake packages
@@ -313,7 +330,7 @@ func createFakePackage(d *commons.SandboxDomain) {
 	commons.Check(ok)
 	p.Sects = make([]commons.Section, 1)
 	p.Sects[0] = commons.Section{
-		commons.Round(sf.Value, false),
+		commons.Round(sf.Addr, false),
 		commons.Round(sf.Size, true),
 		commons.X_VAL | commons.R_VAL | commons.USER_VAL,
 	}
@@ -321,8 +338,8 @@ func createFakePackage(d *commons.SandboxDomain) {
 	// stack object for sandbox
 	if stkobj, ok := globals.NameToSym[d.Func+".stkobj"]; ok {
 		p.Sects = append(p.Sects, commons.Section{
-			commons.Round(stkobj.Value, false),
-			commons.Round(sf.Size, true),
+			commons.Round(stkobj.Addr, false),
+			commons.Round(stkobj.Size, true),
 			commons.R_VAL | commons.USER_VAL,
 		})
 	}
@@ -330,8 +347,8 @@ func createFakePackage(d *commons.SandboxDomain) {
 	// stack object from main
 	if stkobj, ok := globals.NameToSym["main.main.stkobj"]; ok {
 		p.Sects = append(p.Sects, commons.Section{
-			commons.Round(stkobj.Value, false),
-			commons.Round(sf.Size, true),
+			commons.Round(stkobj.Addr, false),
+			commons.Round(stkobj.Size, true),
 			commons.R_VAL | commons.USER_VAL,
 		})
 	}
@@ -2,6 +2,7 @@ package kvm
 
 import (
 	"gosb/commons"
+	"gosb/globals"
 	"gosb/vtx/arch"
 	"sync/atomic"
 	"syscall"
@@ -192,6 +193,9 @@ func bluepillHandler(context unsafe.Pointer) {
 			case syshandlerException:
 				c.die(bluepillArchContext(context), "Received an exception")
 				return
+			case syshandlerGuard:
+				c.die(bluepillArchContext(context), globals.GuardViolation(uint64(MRTFault)))
+				return
 			default:
 				throw("Something went wrong not identified")
 			}
@@ -2,6 +2,7 @@ package kvm
 
 import (
 	c "gosb/commons"
+	"gosb/globals"
 	"gosb/vtx/platform/memview"
 	"gosb/vtx/platform/ring0"
 	"runtime"
@@ -25,6 +26,7 @@ const (
 	syshandlerValid     sysHType = iota // valid system call
 	syshandlerInvalid   sysHType = iota // unallowed system call
 	syshandlerBail      sysHType = iota // redpill
+	syshandlerGuard     sysHType = iota // access to a guard page
 )
 
 var (
@@ -114,6 +116,10 @@ func kvmSyscallHandler(vcpu *VCPU) sysHType {
 	}
 
 	if vcpu.exceptionCode == int(ring0.PageFault) {
+		if globals.GuardViolation(uint64(vcpu.FaultAddr)) != "" {
+			MRTFault = vcpu.FaultAddr
+			return syshandlerGuard
+		}
 		// Lock as it might be modified
 		vcpu.machine.Mu.Lock()
 
@@ -2,6 +2,7 @@ package memview
 
 import (
 	c "gosb/commons"
+	"gosb/globals"
 	pg "gosb/vtx/platform/ring0/pagetables"
 	"io/ioutil"
 	"log"
@@ -64,6 +65,11 @@ func InitializeGod() {
 	FreeSpace.Initialize(free, false)
 	GodAS.FreeAllocator = FreeSpace
 
+	// Guard pages are never mapped, not even in the GodAS.
+	for _, g := range globals.Guards {
+		full.Unmap(c.SectVMA(&g.Section))
+	}
+
 	// Create the page tables
 	GodAS.PTEAllocator = &PageTableAllocator{}
 	GodAS.PTEAllocator.Initialize(GodAS.FreeAllocator)
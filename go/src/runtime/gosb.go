@@ -47,13 +47,43 @@ var (
 	Redpill           func()                                         = nil
 )
 
+// sandbox_prolog enters the sandbox id. Unless compiled with -gosbdefer,
+// sandboxes do not defer their epilog, we therefore record the frame of
+// the sandbox for sandboxUnwind.
+// Sandboxes cannot nest: the epilog returns to the trusted domain, the
+// outer sandbox would run unprotected after the inner one.
+//
 //go:nosplit
 func sandbox_prolog(id, mem, syscalls string) {
+	gp := getg()
+	if gp.sbframe != 0 {
+		print("gosb: sandbox ", id, " entered from sandbox ", gp.sbframeid, "\n")
+		throw("nested sandboxes")
+	}
 	prologHook(id)
+	gp.sbframe = gp.stack.hi - getcallersp()
+	gp.sbframeid = id
 }
 
 //go:nosplit
 func sandbox_epilog(id, mem, syscalls string) {
+	gp := getg()
+	gp.sbframe = 0
+	gp.sbframeid = ""
+	epilogHook(id)
+}
+
+// sandboxUnwind runs the epilog of the sandbox being executed by gp if a
+// panic or Goexit unwinds its frame before running the deferred calls of
+// the frame at sp. Frames are compared by their distance to the top of the
+// stack, which does not change when the stack is copied.
+func sandboxUnwind(gp *g, sp uintptr) {
+	if gp.sbframe == 0 || gp.sbframe <= gp.stack.hi-sp {
+		return
+	}
+	id := gp.sbframeid
+	gp.sbframe = 0
+	gp.sbframeid = ""
 	epilogHook(id)
 }
 
@@ -99,10 +129,43 @@ func LitterboxHooks(
 	bloatInitDone = true
 }
 
+// Number of bases the gosb metadata is relative to.
+// Must match gosbBases in cmd/link/internal/ld/gosb2.go.
+const _GOSB_BASES = 11
+
+// gosbsections is filled by the linker with the addresses of the .bloated,
+// .sandboxes and .gosbsyms sections and of the start of the Go sections when
+// the binary has sandboxes. The addresses are relocated when the binary is a
+// PIE or is linked externally.
+var gosbsections struct {
+	bloated, sandboxes, syms unsafe.Pointer
+	bases                    [_GOSB_BASES]unsafe.Pointer
+} // linker symbol
+
+// GosbSections returns the bases the gosb metadata is relative to and the
+// addresses of the gosb sections in the loaded image, or nil if the binary
+// does not have sandboxes.
+func GosbSections() (bases []uintptr, bloated, sandboxes, syms unsafe.Pointer) {
+	bases = make([]uintptr, len(gosbsections.bases))
+	for i, b := range gosbsections.bases {
+		bases[i] = uintptr(b)
+	}
+	return bases, gosbsections.bloated, gosbsections.sandboxes, gosbsections.syms
+}
+
 func RegisterEmergencyGrowth(f func(bool, int, uintptr, uintptr)) {
 	runtimeGrowth = f
 }
 
+// guardFault returns the report for a fault on a guard page, "" otherwise.
+var guardFault func(addr uintptr) string = nil
+
+// RegisterGuardFault registers f to identify faults on the guard pages the
+// linker inserts between packages. sigpanic reports them as violations.
+func RegisterGuardFault(f func(addr uintptr) string) {
+	guardFault = f
+}
+
 // AssignSbId acquires assigns g.sbid == m.sbid == id
 // This might change g0? Should we make it explicit?
 //
@@ -1203,6 +1203,19 @@ func newobject(typ *_type, id int) unsafe.Pointer {
 	return p
 }
 
+// newsandboxobject allocates the state captured by the sandbox closure sb,
+// i.e., its closure record and the variables it captures by reference. The
+// memory is attributed to the sandbox rather than to the package id, so
+// that the sandbox can access it regardless of its view.
+func newsandboxobject(typ *_type, id int, sb string) unsafe.Pointer {
+	if bloatInitDone {
+		if sbid, ok := pkgToId[sb]; ok {
+			return mallocgc(typ.size, typ, true, sbid)
+		}
+	}
+	return newobject(typ, id)
+}
+
 //go:linkname reflect_unsafe_New reflect.unsafe_New
 func reflect_unsafe_New(typ *_type) unsafe.Pointer {
 	return mallocgc(typ.size, typ, true, gosbInterpose(CALLER_LVL))
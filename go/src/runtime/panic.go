@@ -559,6 +559,7 @@ func Goexit() {
 			freedefer(d)
 			continue
 		}
+		sandboxUnwind(gp, d.sp)
 		d.started = true
 		reflectcall(nil, unsafe.Pointer(d.fn), deferArgs(d), uint32(d.siz), uint32(d.siz))
 		if gp._defer != d {
@@ -570,6 +571,7 @@ func Goexit() {
 		freedefer(d)
 		// Note: we ignore recovers here because Goexit isn't a panic
 	}
+	sandboxUnwind(gp, gp.stack.hi)
 	goexit1()
 }
 
@@ -665,6 +667,9 @@ func gopanic(e interface{}) {
 			continue
 		}
 
+		// Leave the sandboxes unwound before reaching the frame of d.
+		sandboxUnwind(gp, d.sp)
+
 		// Mark defer as started, but keep on list, so that traceback
 		// can find and update the defer's argument frame if stack growth
 		// or a garbage collection happens before reflectcall starts executing d.fn.
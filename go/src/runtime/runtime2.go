@@ -437,6 +437,8 @@ type g struct {
 	timer          *timer         // cached timer for time.Sleep
 	selectDone     uint32         // are we participating in a select and did someone win the race?
 	sbid           string         // gosb sandbox ID
+	sbframe        uintptr        // stack.hi - sp of the frame of the sandbox being executed, 0 if none
+	sbframeid      string         // id of the sandbox at sbframe
 	vcpu           uintptr        // pointer to the current vcpu
 	// Per-G GC state
 
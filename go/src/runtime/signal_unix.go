@@ -588,6 +588,13 @@ func sigpanic() {
 		if (g.sigcode0 == 0 || g.sigcode0 == _SEGV_MAPERR || g.sigcode0 == _SEGV_ACCERR) && g.sigcode1 < 0x1000 {
 			panicmem()
 		}
+		// @aghosn, accesses to guard pages are sandbox violations.
+		if guardFault != nil {
+			if msg := guardFault(g.sigcode1); msg != "" {
+				print(msg, "\n")
+				throw("sandbox violation")
+			}
+		}
 		// Support runtime/debug.SetPanicOnFault.
 		if g.paniconfault {
 			panicmem()